@@ -0,0 +1,165 @@
+// Package address renders lease company and hirer addresses into Royal Mail style postal address blocks
+package address
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/transfer360/go-transfer360/country"
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
+// DefaultMaxLineLength - longest line used when Options.MaxLineLength is not set, fits a standard DL window envelope
+const DefaultMaxLineLength = 35
+
+// Address - an address before formatting, the lines are in the order they are held in the source record
+type Address struct {
+	Name         string
+	Organisation string
+	Lines        []string
+	Postcode     string
+	Country      string
+}
+
+// Options - controls how an address is formatted
+type Options struct {
+	// MaxLineLength - lines longer than this are wrapped at a word boundary, 0 uses DefaultMaxLineLength and a
+	// negative value disables wrapping
+	MaxLineLength int
+	// UppercaseTown - print the last address line (the post town) in capitals as Royal Mail recommends
+	UppercaseTown bool
+	// OmitCountry - never print a country line, even for addresses outside the UK
+	OmitCountry bool
+}
+
+// Formatted - a cleaned address block ready for printing
+type Formatted struct {
+	Recipient    string   `json:"recipient,omitempty"`
+	Organisation string   `json:"organisation,omitempty"`
+	Street       []string `json:"street,omitempty"`
+	Postcode     string   `json:"postcode,omitempty"`
+	Country      string   `json:"country,omitempty"`
+	Lines        []string `json:"lines"`
+}
+
+// Format cleans an address and renders it as a postal address block. Blank and repeated lines are removed, UK
+// postcodes are normalised and a country line is added in capitals for addresses outside the UK.
+func Format(a Address, opts Options) Formatted {
+
+	maxLen := opts.MaxLineLength
+	if maxLen == 0 {
+		maxLen = DefaultMaxLineLength
+	}
+
+	f := Formatted{
+		Recipient:    clean(a.Name),
+		Organisation: clean(a.Organisation),
+		Postcode:     clean(a.Postcode),
+		Country:      clean(a.Country),
+	}
+
+	code, err := country.Normalise(f.Country)
+	uk := err == nil && code == country.UnitedKingdom
+	if uk {
+		f.Country = ""
+	} else if err == nil {
+		f.Country = country.Name(code)
+	}
+	if err == nil {
+		if pc, err := postcode.NormaliseFor(code, f.Postcode); err == nil {
+			f.Postcode = pc
+		}
+	}
+
+	seen := map[string]bool{
+		strings.ToUpper(f.Recipient):    true,
+		strings.ToUpper(f.Organisation): true,
+		strings.ToUpper(f.Postcode):     true,
+		strings.ToUpper(f.Country):      true,
+	}
+	for _, l := range a.Lines {
+		l = clean(l)
+		if seen[strings.ToUpper(l)] {
+			continue
+		}
+		seen[strings.ToUpper(l)] = true
+		f.Street = append(f.Street, l)
+	}
+
+	if opts.UppercaseTown && len(f.Street) > 0 {
+		f.Street[len(f.Street)-1] = strings.ToUpper(f.Street[len(f.Street)-1])
+	}
+
+	lines := []string{f.Recipient, f.Organisation}
+	lines = append(lines, f.Street...)
+	lines = append(lines, f.Postcode)
+	if !uk && !opts.OmitCountry {
+		lines = append(lines, strings.ToUpper(f.Country))
+	}
+
+	for _, l := range lines {
+		if len(l) == 0 {
+			continue
+		}
+		f.Lines = append(f.Lines, wrap(l, maxLen)...)
+	}
+
+	return f
+}
+
+// String returns the address block with one line per row
+func (f Formatted) String() string {
+	return strings.Join(f.Lines, "\n")
+}
+
+// SingleLine returns the address as a single comma separated line
+func (f Formatted) SingleLine() string {
+	return strings.Join(f.Lines, ", ")
+}
+
+// JSON returns the structured address as JSON
+func (f Formatted) JSON() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+func clean(s string) string {
+	return strings.Trim(strings.Join(strings.Fields(s), " "), ",")
+}
+
+// wrap splits a line at word boundaries so no line is longer than maxLen characters, words longer than maxLen are
+// split. Lengths are counted in runes so accented names are not cut mid character.
+func wrap(line string, maxLen int) []string {
+
+	if maxLen < 0 || utf8.RuneCountInString(line) <= maxLen {
+		return []string{line}
+	}
+
+	var out []string
+	var current []rune
+	for _, field := range strings.Fields(line) {
+		word := []rune(field)
+		for len(word) > maxLen {
+			if len(current) > 0 {
+				out = append(out, string(current))
+				current = nil
+			}
+			out = append(out, string(word[:maxLen]))
+			word = word[maxLen:]
+		}
+		switch {
+		case len(current) == 0:
+			current = word
+		case len(current)+1+len(word) <= maxLen:
+			current = append(append(current, ' '), word...)
+		default:
+			out = append(out, string(current))
+			current = word
+		}
+	}
+	if len(current) > 0 {
+		out = append(out, string(current))
+	}
+
+	return out
+}
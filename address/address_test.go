@@ -0,0 +1,61 @@
+package address
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWrap(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		line   string
+		maxLen int
+		want   []string
+	}{
+		{"short", "1 High Street", 35, []string{"1 High Street"}},
+		{"disabled", "Unit 4 Riverside Industrial Estate Old Mill Lane", -1, []string{"Unit 4 Riverside Industrial Estate Old Mill Lane"}},
+		{"word boundary", "Unit 4 Riverside Industrial Estate Old Mill Lane", 20, []string{"Unit 4 Riverside", "Industrial Estate", "Old Mill Lane"}},
+		{"long word", "Llanfairpwllgwyngyll", 8, []string{"Llanfair", "pwllgwyn", "gyll"}},
+		{"multibyte within limit", "Château de Sénéchal", 19, []string{"Château de Sénéchal"}},
+		{"multibyte wrapped", "Rue de l'Évêché Saint-Étienne", 15, []string{"Rue de l'Évêché", "Saint-Étienne"}},
+		{"multibyte long word", "ÉÉÉÉÉÉ", 4, []string{"ÉÉÉÉ", "ÉÉ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrap(tt.line, tt.maxLen)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			for _, l := range got {
+				if !utf8.ValidString(l) {
+					t.Errorf("line %q is not valid utf-8", l)
+				}
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+
+	f := Format(Address{
+		Name:     " Jane  Smith ",
+		Lines:    []string{"1 High Street", "", "1 high street", "Leeds"},
+		Postcode: "ls14dy",
+		Country:  "United Kingdom",
+	}, Options{UppercaseTown: true})
+
+	want := []string{"Jane Smith", "1 High Street", "LEEDS", "LS1 4DY"}
+	if !reflect.DeepEqual(f.Lines, want) {
+		t.Errorf("got %q, want %q", f.Lines, want)
+	}
+
+	f = Format(Address{Name: "Sean Murphy", Lines: []string{"1 Main Street", "Dublin"}, Postcode: "d02x285", Country: "ie"}, Options{})
+
+	want = []string{"Sean Murphy", "1 Main Street", "Dublin", "D02 X285", "IRELAND"}
+	if !reflect.DeepEqual(f.Lines, want) {
+		t.Errorf("got %q, want %q", f.Lines, want)
+	}
+}
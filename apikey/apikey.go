@@ -0,0 +1,86 @@
+// Package apikey resolves the Transfer360 API key to use for an issuer, for software providers holding a different
+// key per client
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrUnknownIssuer = errors.New("no API key for issuer")
+var ErrMissingIssuerID = errors.New("missing issuer id")
+
+// APIKeyResolver - returns the API key to use for an issuer, ErrUnknownIssuer is returned if there isn't one
+type APIKeyResolver interface {
+	APIKey(ctx context.Context, issuerID string) (string, error)
+}
+
+func unknown(issuerID string) error {
+	return fmt.Errorf("%w [%s]", ErrUnknownIssuer, issuerID)
+}
+
+// StaticResolver - API keys held in a map of issuer id to key
+type StaticResolver map[string]string
+
+func (s StaticResolver) APIKey(_ context.Context, issuerID string) (string, error) {
+	if len(issuerID) == 0 {
+		return "", ErrMissingIssuerID
+	}
+	key, ok := s[issuerID]
+	if !ok || len(key) == 0 {
+		return "", unknown(issuerID)
+	}
+	return key, nil
+}
+
+// CachedResolver - caches keys from another resolver for a fixed time, unknown issuers are not cached so a newly
+// added key is picked up straight away
+type CachedResolver struct {
+	resolver APIKeyResolver
+	ttl      time.Duration
+	mu       sync.Mutex
+	keys     map[string]cachedKey
+	now      func() time.Time
+}
+
+type cachedKey struct {
+	key     string
+	expires time.Time
+}
+
+// NewCachedResolver wraps a resolver with a cache, keys are kept for ttl
+func NewCachedResolver(resolver APIKeyResolver, ttl time.Duration) *CachedResolver {
+	return &CachedResolver{resolver: resolver, ttl: ttl, keys: map[string]cachedKey{}, now: time.Now}
+}
+
+func (c *CachedResolver) APIKey(ctx context.Context, issuerID string) (string, error) {
+
+	c.mu.Lock()
+	ck, ok := c.keys[issuerID]
+	c.mu.Unlock()
+
+	if ok && c.now().Before(ck.expires) {
+		return ck.key, nil
+	}
+
+	key, err := c.resolver.APIKey(ctx, issuerID)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.keys[issuerID] = cachedKey{key: key, expires: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return key, nil
+}
+
+// Forget drops the cached key for an issuer, e.g. after the API server rejects it
+func (c *CachedResolver) Forget(issuerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.keys, issuerID)
+}
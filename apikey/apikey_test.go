@@ -0,0 +1,159 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStaticResolver(t *testing.T) {
+
+	r := StaticResolver{"ISSUER1": "key-one", "ISSUER2": ""}
+
+	tests := []struct {
+		issuerID string
+		want     string
+		err      error
+	}{
+		{"ISSUER1", "key-one", nil},
+		{"ISSUER2", "", ErrUnknownIssuer},
+		{"ISSUER3", "", ErrUnknownIssuer},
+		{"", "", ErrMissingIssuerID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.issuerID, func(t *testing.T) {
+			got, err := r.APIKey(context.Background(), tt.issuerID)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvResolver(t *testing.T) {
+
+	t.Setenv("T360_API_KEY_ISSUER_123", "key-env")
+	t.Setenv("CUSTOM_ABC", "key-custom")
+
+	tests := []struct {
+		name     string
+		resolver EnvResolver
+		issuerID string
+		variable string
+		want     string
+		err      error
+	}{
+		{"default prefix", EnvResolver{}, "issuer-123", "T360_API_KEY_ISSUER_123", "key-env", nil},
+		{"custom prefix", EnvResolver{Prefix: "CUSTOM_"}, "abc", "CUSTOM_ABC", "key-custom", nil},
+		{"not set", EnvResolver{}, "issuer 999", "T360_API_KEY_ISSUER_999", "", ErrUnknownIssuer},
+		{"missing issuer id", EnvResolver{}, "", "T360_API_KEY_", "", ErrMissingIssuerID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resolver.VariableName(tt.issuerID); got != tt.variable {
+				t.Errorf("variable %q, want %q", got, tt.variable)
+			}
+			got, err := tt.resolver.APIKey(context.Background(), tt.issuerID)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFileResolver(t *testing.T) {
+
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(path, []byte(`{"ISSUER1": "key-one"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewFileResolver(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key, err := r.APIKey(context.Background(), "ISSUER1"); err != nil || key != "key-one" {
+		t.Errorf("got %q %v", key, err)
+	}
+	if _, err = r.APIKey(context.Background(), "ISSUER2"); !errors.Is(err, ErrUnknownIssuer) {
+		t.Errorf("got %v, want %v", err, ErrUnknownIssuer)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err = os.WriteFile(bad, []byte(`["ISSUER1"]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewFileResolver(bad); err == nil {
+		t.Error("expected an error for a file which is not an object")
+	}
+	if _, err = NewFileResolver(filepath.Join(dir, "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+// countingResolver counts lookups and fails for issuers without a key
+type countingResolver struct {
+	keys    map[string]string
+	lookups int
+}
+
+func (c *countingResolver) APIKey(ctx context.Context, issuerID string) (string, error) {
+	c.lookups++
+	return StaticResolver(c.keys).APIKey(ctx, issuerID)
+}
+
+func TestCachedResolver(t *testing.T) {
+
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	inner := &countingResolver{keys: map[string]string{"ISSUER1": "key-one"}}
+	c := NewCachedResolver(inner, time.Minute)
+	c.now = func() time.Time { return now }
+
+	lookup := func(issuerID string, want string, wantErr error, wantLookups int) {
+		t.Helper()
+		got, err := c.APIKey(ctx, issuerID)
+		if !errors.Is(err, wantErr) || got != want {
+			t.Errorf("got %q %v, want %q %v", got, err, want, wantErr)
+		}
+		if inner.lookups != wantLookups {
+			t.Errorf("got %d lookups, want %d", inner.lookups, wantLookups)
+		}
+	}
+
+	lookup("ISSUER1", "key-one", nil, 1)
+	lookup("ISSUER1", "key-one", nil, 1)
+
+	// a changed key is not seen until the cached one expires
+	inner.keys["ISSUER1"] = "key-two"
+	now = now.Add(59 * time.Second)
+	lookup("ISSUER1", "key-one", nil, 1)
+	now = now.Add(time.Second)
+	lookup("ISSUER1", "key-two", nil, 2)
+
+	// Forget drops the cached key straight away
+	inner.keys["ISSUER1"] = "key-three"
+	c.Forget("ISSUER1")
+	lookup("ISSUER1", "key-three", nil, 3)
+
+	// unknown issuers are not cached so a key added later is used
+	lookup("ISSUER2", "", ErrUnknownIssuer, 4)
+	inner.keys["ISSUER2"] = "key-new"
+	lookup("ISSUER2", "key-new", nil, 5)
+
+	lookup("", "", ErrMissingIssuerID, 6)
+}
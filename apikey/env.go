@@ -0,0 +1,47 @@
+package apikey
+
+import (
+	"context"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// DefaultEnvPrefix - prefix of the environment variables read by EnvResolver when no prefix is given
+const DefaultEnvPrefix = "T360_API_KEY_"
+
+// EnvResolver - reads API keys from environment variables named Prefix followed by the issuer id in upper case with
+// anything other than letters and digits replaced by "_", e.g. T360_API_KEY_ISSUER_123
+type EnvResolver struct {
+	Prefix string
+}
+
+func (e EnvResolver) APIKey(_ context.Context, issuerID string) (string, error) {
+
+	if len(issuerID) == 0 {
+		return "", ErrMissingIssuerID
+	}
+
+	key := os.Getenv(e.VariableName(issuerID))
+	if len(key) == 0 {
+		return "", unknown(issuerID)
+	}
+
+	return key, nil
+}
+
+// VariableName returns the environment variable holding the issuer's key
+func (e EnvResolver) VariableName(issuerID string) string {
+
+	prefix := e.Prefix
+	if len(prefix) == 0 {
+		prefix = DefaultEnvPrefix
+	}
+
+	return prefix + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, issuerID)
+}
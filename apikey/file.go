@@ -0,0 +1,27 @@
+package apikey
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// NewFileResolver loads API keys from a JSON file holding an object of issuer id to key, e.g.
+//
+//	{"ISSUER123": "key-one", "ISSUER456": "key-two"}
+//
+// The file is read once, create a new resolver to pick up changes.
+func NewFileResolver(path string) (StaticResolver, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading API key file: %w", err)
+	}
+
+	keys := map[string]string{}
+	if err = json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("reading API key file %s: %w", path, err)
+	}
+
+	return StaticResolver(keys), nil
+}
@@ -0,0 +1,49 @@
+package apikey
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	log "github.com/sirupsen/logrus"
+)
+
+const ISSUER_API_KEYS_COLLECTION = "issuer_api_keys"
+
+// FirestoreResolver - reads API keys from the issuer_api_keys collection, one document per issuer with the issuer id
+// as the document id and the key in the api_key field
+type FirestoreResolver struct {
+	fs *firestore.Client
+}
+
+func NewFirestoreResolver(fs *firestore.Client) *FirestoreResolver {
+	return &FirestoreResolver{fs: fs}
+}
+
+func (f *FirestoreResolver) APIKey(ctx context.Context, issuerID string) (string, error) {
+
+	if len(issuerID) == 0 {
+		return "", ErrMissingIssuerID
+	}
+
+	doc, err := f.fs.Collection(ISSUER_API_KEYS_COLLECTION).Doc(issuerID).Get(ctx)
+	if err != nil {
+		if doc != nil && !doc.Exists() {
+			return "", unknown(issuerID)
+		}
+		log.Errorf("FirestoreResolver.APIKey:[%s]:%v", issuerID, err)
+		return "", err
+	}
+
+	data := struct {
+		APIKey string `firestore:"api_key"`
+	}{}
+	if err = doc.DataTo(&data); err != nil {
+		log.Errorf("FirestoreResolver.APIKey:[%s]:%v", issuerID, err)
+		return "", err
+	}
+
+	if len(data.APIKey) == 0 {
+		return "", unknown(issuerID)
+	}
+
+	return data.APIKey, nil
+}
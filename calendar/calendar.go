@@ -0,0 +1,222 @@
+// Package calendar provides UK working day calculations using the bank holidays of England and Wales, Scotland and
+// Northern Ireland. Bank holidays for 2024 to 2027 are embedded, later years can be loaded from a file in the format
+// published at https://www.gov.uk/bank-holidays.json
+package calendar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
+//go:embed bank_holidays.json
+var embeddedHolidays []byte
+
+var ErrUnknownJurisdiction = errors.New("no bank holidays for jurisdiction")
+
+// PostalDeliveryWorkingDays - a notice sent by first class post is deemed served on the second working day after posting
+const PostalDeliveryWorkingDays = 2
+
+// divisions - gov.uk division names for each jurisdiction
+var divisions = map[string]postcode.Jurisdiction{
+	"england-and-wales": postcode.EnglandAndWales,
+	"scotland":          postcode.Scotland,
+	"northern-ireland":  postcode.NorthernIreland,
+}
+
+type govUKFile map[string]struct {
+	Events []struct {
+		Title string `json:"title"`
+		Date  string `json:"date"`
+	} `json:"events"`
+}
+
+var (
+	mu       sync.RWMutex
+	holidays = map[postcode.Jurisdiction]map[string]string{}
+	lastYear = map[postcode.Jurisdiction]int{}
+)
+
+func init() {
+	if err := load(embeddedHolidays); err != nil {
+		panic(fmt.Sprintf("calendar: embedded bank holidays: %v", err))
+	}
+}
+
+// LoadFile adds the bank holidays in a gov.uk bank-holidays.json file to those already known, use it to extend the
+// calendar beyond the embedded years
+func LoadFile(path string) error {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading bank holidays: %w", err)
+	}
+
+	if err = load(data); err != nil {
+		return fmt.Errorf("reading bank holidays %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func load(data []byte) error {
+
+	file := govUKFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for division, events := range file {
+		j, ok := divisions[division]
+		if !ok {
+			continue
+		}
+		if holidays[j] == nil {
+			holidays[j] = map[string]string{}
+		}
+		for _, e := range events.Events {
+			t, err := time.Parse("2006-01-02", e.Date)
+			if err != nil {
+				return fmt.Errorf("%s bank holiday %q: %w", division, e.Title, err)
+			}
+			holidays[j][e.Date] = e.Title
+			if t.Year() > lastYear[j] {
+				lastYear[j] = t.Year()
+			}
+		}
+	}
+
+	return nil
+}
+
+// Calendar - working days for one jurisdiction, Monday to Friday excluding bank holidays
+type Calendar struct {
+	jurisdiction postcode.Jurisdiction
+}
+
+// For returns the calendar for a jurisdiction. Crown Dependencies and BFPO addresses have no bank holidays here and
+// return ErrUnknownJurisdiction.
+func For(j postcode.Jurisdiction) (Calendar, error) {
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if _, ok := holidays[j]; !ok {
+		return Calendar{}, fmt.Errorf("%w [%s]", ErrUnknownJurisdiction, j)
+	}
+
+	return Calendar{jurisdiction: j}, nil
+}
+
+// EnglandAndWales - the calendar used when the jurisdiction is not known
+func EnglandAndWales() Calendar {
+	return Calendar{jurisdiction: postcode.EnglandAndWales}
+}
+
+// Jurisdiction returns the jurisdiction the calendar is for
+func (c Calendar) Jurisdiction() postcode.Jurisdiction {
+	return c.jurisdiction
+}
+
+// Covers returns true if bank holidays are known for the year of t, dates after the last known year are treated as
+// having no bank holidays
+func (c Calendar) Covers(t time.Time) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return Day(t).Year() <= lastYear[c.jurisdiction]
+}
+
+// BankHoliday returns the name of the bank holiday on the UK day t falls on, or "" if it is not a bank holiday
+func (c Calendar) BankHoliday(t time.Time) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return holidays[c.jurisdiction][Day(t).Format("2006-01-02")]
+}
+
+// IsWorkingDay returns true if the UK day t falls on is a weekday and not a bank holiday
+func (c Calendar) IsWorkingDay(t time.Time) bool {
+	d := Day(t)
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	return len(c.BankHoliday(d)) == 0
+}
+
+// AddWorkingDays returns the day n working days after t, or before t if n is negative. The result is midnight UTC of
+// the UK day, see Day.
+func (c Calendar) AddWorkingDays(t time.Time, n int) time.Time {
+
+	d := Day(t)
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	for n > 0 {
+		d = d.AddDate(0, 0, step)
+		if c.IsWorkingDay(d) {
+			n--
+		}
+	}
+
+	return d
+}
+
+// WorkingDaysBetween counts the working days after from up to and including to, it is negative if to is before from
+func (c Calendar) WorkingDaysBetween(from, to time.Time) int {
+
+	start, end := Day(from), Day(to)
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+
+	count := 0
+	for d := start.AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(d) {
+			count++
+		}
+	}
+
+	return sign * count
+}
+
+// DeemedServed returns the day a notice posted on t by first class post is deemed served, the second working day
+// after posting
+func (c Calendar) DeemedServed(posted time.Time) time.Time {
+	return c.AddWorkingDays(posted, PostalDeliveryWorkingDays)
+}
+
+// LatestPosting returns the last day a notice can be posted and still be deemed served by the deadline
+func (c Calendar) LatestPosting(deadline time.Time) time.Time {
+	d := Day(deadline)
+	for c.DeemedServed(d).After(Day(deadline)) {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+var london = loadLondon()
+
+func loadLondon() *time.Location {
+	loc, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Day returns midnight UTC of the UK calendar day t falls on, so day arithmetic is not affected by clock changes
+func Day(t time.Time) time.Time {
+	y, m, d := t.In(london).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
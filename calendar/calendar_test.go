@@ -0,0 +1,124 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func mustFor(t *testing.T, j postcode.Jurisdiction) Calendar {
+	t.Helper()
+	c, err := For(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestIsWorkingDay(t *testing.T) {
+
+	tests := []struct {
+		jurisdiction postcode.Jurisdiction
+		day          string
+		want         bool
+	}{
+		{postcode.EnglandAndWales, "2025-06-02", true},
+		{postcode.EnglandAndWales, "2025-06-07", false}, // Saturday
+		{postcode.EnglandAndWales, "2025-04-18", false}, // Good Friday
+		{postcode.EnglandAndWales, "2025-08-04", true},
+		{postcode.Scotland, "2025-08-04", false}, // summer bank holiday
+		{postcode.Scotland, "2025-08-25", true},
+		{postcode.NorthernIreland, "2025-03-17", false}, // St Patrick's Day
+		{postcode.NorthernIreland, "2025-07-14", false}, // Battle of the Boyne
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.jurisdiction)+" "+tt.day, func(t *testing.T) {
+			if got := mustFor(t, tt.jurisdiction).IsWorkingDay(date(tt.day)); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPosting(t *testing.T) {
+
+	c := EnglandAndWales()
+
+	tests := []struct {
+		name     string
+		posted   string
+		served   string
+		deadline string
+		latest   string
+	}{
+		{"midweek", "2025-06-03", "2025-06-05", "2025-06-05", "2025-06-03"},
+		{"over a weekend", "2025-06-05", "2025-06-09", "2025-06-09", "2025-06-05"},
+		{"over easter", "2025-04-17", "2025-04-23", "2025-04-23", "2025-04-21"}, // posting on Easter Monday is still served on the Wednesday
+		{"deadline on a sunday", "2025-06-11", "2025-06-13", "2025-06-15", "2025-06-11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.DeemedServed(date(tt.posted)); !got.Equal(date(tt.served)) {
+				t.Errorf("DeemedServed got %s, want %s", got.Format("2006-01-02"), tt.served)
+			}
+			if got := c.LatestPosting(date(tt.deadline)); !got.Equal(date(tt.latest)) {
+				t.Errorf("LatestPosting got %s, want %s", got.Format("2006-01-02"), tt.latest)
+			}
+		})
+	}
+}
+
+func TestWorkingDays(t *testing.T) {
+
+	c := EnglandAndWales()
+
+	if got := c.AddWorkingDays(date("2025-04-22"), -2); !got.Equal(date("2025-04-16")) {
+		t.Errorf("AddWorkingDays got %s", got.Format("2006-01-02"))
+	}
+	if got := c.WorkingDaysBetween(date("2025-04-17"), date("2025-04-23")); got != 2 {
+		t.Errorf("WorkingDaysBetween got %d, want 2", got)
+	}
+	if got := c.WorkingDaysBetween(date("2025-04-23"), date("2025-04-17")); got != -2 {
+		t.Errorf("WorkingDaysBetween got %d, want -2", got)
+	}
+}
+
+func TestCovers(t *testing.T) {
+
+	c := EnglandAndWales()
+	if !c.Covers(date("2027-12-31")) {
+		t.Error("expected 2027 to be covered")
+	}
+	if c.Covers(date("2028-01-01")) {
+		t.Error("expected 2028 not to be covered")
+	}
+}
+
+func TestDay(t *testing.T) {
+
+	// 23:30 UTC in summer is the next day in the UK
+	if got := Day(time.Date(2025, 6, 1, 23, 30, 0, 0, time.UTC)); !got.Equal(date("2025-06-02")) {
+		t.Errorf("got %s", got)
+	}
+	if got := Day(time.Date(2025, 1, 1, 23, 30, 0, 0, time.UTC)); !got.Equal(date("2025-01-01")) {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestFor(t *testing.T) {
+	if _, err := For(postcode.CrownDependency); !errors.Is(err, ErrUnknownJurisdiction) {
+		t.Errorf("got %v, want %v", err, ErrUnknownJurisdiction)
+	}
+}
@@ -0,0 +1,154 @@
+// Package charge models parking charge amounts, the discount for early payment and any escalation for late payment,
+// checked against the caps set by the code of practice. Amounts are in pence.
+package charge
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/transfer360/go-transfer360/calendar"
+)
+
+var ErrInvalidSchedule = errors.New("invalid charge schedule")
+var ErrExceedsCap = errors.New("charge schedule exceeds code of practice caps")
+
+// Stage - which part of the schedule an amount comes from
+type Stage string
+
+const (
+	StageDiscount  Stage = "discount"
+	StageFull      Stage = "full"
+	StageEscalated Stage = "escalated"
+)
+
+// Escalation - an amount added to the full charge once a number of days have passed since the notice was issued,
+// e.g. debt recovery costs
+type Escalation struct {
+	AfterDays   int    `json:"after_days"`
+	Amount      int    `json:"amount"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schedule - the amounts payable for a parking charge
+type Schedule struct {
+	// FullAmount - the parking charge in pence
+	FullAmount int `json:"full_amount"`
+	// DiscountAmount - the reduced amount for early payment in pence, 0 if no discount is offered
+	DiscountAmount int `json:"discount_amount,omitempty"`
+	// DiscountDays - the discount is payable up to and including this many days after the issue date
+	DiscountDays int          `json:"discount_days,omitempty"`
+	Escalations  []Escalation `json:"escalations,omitempty"`
+}
+
+// Caps - limits a schedule is validated against, a zero value means no limit
+type Caps struct {
+	MaxFullAmount       int `json:"max_full_amount"`
+	MinDiscountPercent  int `json:"min_discount_percent"`
+	MinDiscountDays     int `json:"min_discount_days"`
+	MaxEscalationAmount int `json:"max_escalation_amount"`
+}
+
+// DefaultCaps - the higher tier caps of the private parking single code of practice, £100 charge, at least 40% discount
+// for payment within 14 days and at most £70 of debt recovery costs
+var DefaultCaps = Caps{
+	MaxFullAmount:       10000,
+	MinDiscountPercent:  40,
+	MinDiscountDays:     14,
+	MaxEscalationAmount: 7000,
+}
+
+// Payable - the amount due on a given day
+type Payable struct {
+	Amount int   `json:"amount"`
+	Stage  Stage `json:"stage"`
+	// Until - last day this amount applies, nil if it applies indefinitely
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// Validate checks the schedule is consistent and within the caps
+func (s Schedule) Validate(caps Caps) error {
+
+	if s.FullAmount <= 0 {
+		return fmt.Errorf("%w: full amount must be more than zero", ErrInvalidSchedule)
+	}
+
+	if s.DiscountAmount < 0 || s.DiscountAmount >= s.FullAmount {
+		return fmt.Errorf("%w: discount amount must be less than the full amount", ErrInvalidSchedule)
+	}
+
+	if s.DiscountAmount > 0 && s.DiscountDays <= 0 {
+		return fmt.Errorf("%w: discount days must be set when a discount is offered", ErrInvalidSchedule)
+	}
+
+	if caps.MaxFullAmount > 0 && s.FullAmount > caps.MaxFullAmount {
+		return fmt.Errorf("%w: full amount %s is more than %s", ErrExceedsCap, Format(s.FullAmount), Format(caps.MaxFullAmount))
+	}
+
+	if s.DiscountAmount > 0 {
+		if caps.MinDiscountPercent > 0 && (s.FullAmount-s.DiscountAmount)*100 < s.FullAmount*caps.MinDiscountPercent {
+			return fmt.Errorf("%w: discount is less than %d%%", ErrExceedsCap, caps.MinDiscountPercent)
+		}
+		if caps.MinDiscountDays > 0 && s.DiscountDays < caps.MinDiscountDays {
+			return fmt.Errorf("%w: discount period is less than %d days", ErrExceedsCap, caps.MinDiscountDays)
+		}
+	}
+
+	escalated := 0
+	for _, e := range s.Escalations {
+		if e.Amount <= 0 || e.AfterDays <= 0 {
+			return fmt.Errorf("%w: escalations need a positive amount and number of days", ErrInvalidSchedule)
+		}
+		if e.AfterDays <= s.DiscountDays {
+			return fmt.Errorf("%w: escalation after %d days is within the discount period", ErrInvalidSchedule, e.AfterDays)
+		}
+		escalated += e.Amount
+	}
+
+	if caps.MaxEscalationAmount > 0 && escalated > caps.MaxEscalationAmount {
+		return fmt.Errorf("%w: escalations of %s are more than %s", ErrExceedsCap, Format(escalated), Format(caps.MaxEscalationAmount))
+	}
+
+	return nil
+}
+
+// AmountPayable returns the amount due on the UK day of on, for a notice issued on the UK day of issued
+func (s Schedule) AmountPayable(issued, on time.Time) Payable {
+
+	issueDay := calendar.Day(issued)
+	days := int(calendar.Day(on).Sub(issueDay).Hours() / 24)
+
+	if s.DiscountAmount > 0 && days <= s.DiscountDays {
+		until := issueDay.AddDate(0, 0, s.DiscountDays)
+		return Payable{Amount: s.DiscountAmount, Stage: StageDiscount, Until: &until}
+	}
+
+	escalations := make([]Escalation, len(s.Escalations))
+	copy(escalations, s.Escalations)
+	sort.SliceStable(escalations, func(i, j int) bool {
+		return escalations[i].AfterDays < escalations[j].AfterDays
+	})
+
+	p := Payable{Amount: s.FullAmount, Stage: StageFull}
+	for _, e := range escalations {
+		if days <= e.AfterDays {
+			until := issueDay.AddDate(0, 0, e.AfterDays)
+			p.Until = &until
+			break
+		}
+		p.Amount += e.Amount
+		p.Stage = StageEscalated
+	}
+
+	return p
+}
+
+// Format returns an amount in pence as pounds, e.g. 6000 is "£60.00"
+func Format(pence int) string {
+	sign := ""
+	if pence < 0 {
+		sign, pence = "-", -pence
+	}
+	return fmt.Sprintf("%s£%d.%02d", sign, pence/100, pence%100)
+}
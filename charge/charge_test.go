@@ -0,0 +1,100 @@
+package charge
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleValidate(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		caps     Caps
+		wantErr  error
+	}{
+		{"valid", Schedule{FullAmount: 10000, DiscountAmount: 6000, DiscountDays: 14}, DefaultCaps, nil},
+		{"no discount", Schedule{FullAmount: 10000}, DefaultCaps, nil},
+		{"zero full amount", Schedule{}, DefaultCaps, ErrInvalidSchedule},
+		{"discount not less than full", Schedule{FullAmount: 6000, DiscountAmount: 6000, DiscountDays: 14}, DefaultCaps, ErrInvalidSchedule},
+		{"discount without days", Schedule{FullAmount: 10000, DiscountAmount: 6000}, DefaultCaps, ErrInvalidSchedule},
+		{"over full cap", Schedule{FullAmount: 12000}, DefaultCaps, ErrExceedsCap},
+		{"discount too small", Schedule{FullAmount: 10000, DiscountAmount: 7000, DiscountDays: 14}, DefaultCaps, ErrExceedsCap},
+		{"discount period too short", Schedule{FullAmount: 10000, DiscountAmount: 6000, DiscountDays: 10}, DefaultCaps, ErrExceedsCap},
+		{"no caps", Schedule{FullAmount: 50000, DiscountAmount: 49000, DiscountDays: 1}, Caps{}, nil},
+		{
+			"escalation within discount period",
+			Schedule{FullAmount: 10000, DiscountAmount: 6000, DiscountDays: 14, Escalations: []Escalation{{AfterDays: 10, Amount: 7000}}},
+			DefaultCaps, ErrInvalidSchedule,
+		},
+		{
+			"escalations over cap",
+			Schedule{FullAmount: 10000, Escalations: []Escalation{{AfterDays: 28, Amount: 5000}, {AfterDays: 56, Amount: 5000}}},
+			DefaultCaps, ErrExceedsCap,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate(tt.caps)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleAmountPayable(t *testing.T) {
+
+	schedule := Schedule{
+		FullAmount:     10000,
+		DiscountAmount: 6000,
+		DiscountDays:   14,
+		Escalations:    []Escalation{{AfterDays: 56, Amount: 2000}, {AfterDays: 28, Amount: 5000}},
+	}
+	issued := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		days   int
+		amount int
+		stage  Stage
+	}{
+		{"issue day", 0, 6000, StageDiscount},
+		{"last discount day", 14, 6000, StageDiscount},
+		{"day after discount", 15, 10000, StageFull},
+		{"last full day", 28, 10000, StageFull},
+		{"first escalation", 29, 15000, StageEscalated},
+		{"second escalation", 57, 17000, StageEscalated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := schedule.AmountPayable(issued, issued.AddDate(0, 0, tt.days))
+			if p.Amount != tt.amount || p.Stage != tt.stage {
+				t.Fatalf("got %d %s, want %d %s", p.Amount, p.Stage, tt.amount, tt.stage)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+
+	tests := map[int]string{
+		0:     "£0.00",
+		5:     "£0.05",
+		6000:  "£60.00",
+		10050: "£100.50",
+		-250:  "-£2.50",
+	}
+
+	for pence, want := range tests {
+		if got := Format(pence); got != want {
+			t.Errorf("Format(%d) = %s, want %s", pence, got, want)
+		}
+	}
+}
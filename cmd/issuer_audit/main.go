@@ -0,0 +1,65 @@
+// Command issuer_audit checks the registered_issuers and searches collections for integrity problems.
+//
+//	issuer_audit -project my-project [-fix]
+//
+// The report is written to stdout as JSON, the exit code is 1 if any problems were found.
+package main
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"flag"
+	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/issuers"
+	"io"
+	"os"
+)
+
+func main() {
+
+	project := flag.String("project", os.Getenv("GOOGLE_CLOUD_PROJECT"), "Google Cloud project holding the Firestore database")
+	fix := flag.Bool("fix", false, "fill in client.issuer_id on searches which only have the legacy client.issuerid")
+	flag.Parse()
+
+	if len(*project) == 0 {
+		log.Fatal("a project is required, use -project or set GOOGLE_CLOUD_PROJECT")
+	}
+
+	problems, err := run(context.Background(), *project, *fix)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if problems {
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, project string, fix bool) (bool, error) {
+
+	fs, err := firestore.NewClient(ctx, project)
+	if err != nil {
+		return false, err
+	}
+	defer fs.Close()
+
+	report, err := issuers.CheckIntegrity(ctx, fix, fs)
+	if err != nil {
+		return false, err
+	}
+
+	return writeReport(os.Stdout, report)
+}
+
+// writeReport writes the report as indented JSON and returns true if it found any problems
+func writeReport(w io.Writer, report issuers.IntegrityReport) (bool, error) {
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return false, err
+	}
+
+	return report.HasProblems(), nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/transfer360/go-transfer360/issuers"
+	"testing"
+)
+
+func TestWriteReport(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		report       issuers.IntegrityReport
+		wantProblems bool
+	}{
+		{"clean", issuers.IntegrityReport{IssuersChecked: 2, SearchesChecked: 10, Fixed: 3}, false},
+		{"duplicates", issuers.IntegrityReport{DuplicateT360IDs: map[string][]string{"T1": {"d1", "d2"}}}, true},
+		{"legacy searches", issuers.IntegrityReport{LegacyIssuerIDSearches: []issuers.SearchIssue{{DocumentID: "d1", IssuerID: "T1"}}}, true},
+		{"failed fixes", issuers.IntegrityReport{FixFailed: []issuers.FixFailure{{SearchIssue: issuers.SearchIssue{DocumentID: "d1"}, Reason: "aborted"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			problems, err := writeReport(&buf, tt.report)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if problems != tt.wantProblems {
+				t.Errorf("problems %v, want %v", problems, tt.wantProblems)
+			}
+
+			var got issuers.IntegrityReport
+			if err = json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("report is not JSON: %v", err)
+			}
+			if got.IssuersChecked != tt.report.IssuersChecked || got.Fixed != tt.report.Fixed || len(got.FixFailed) != len(tt.report.FixFailed) {
+				t.Errorf("got %+v, want %+v", got, tt.report)
+			}
+		})
+	}
+}
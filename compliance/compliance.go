@@ -0,0 +1,163 @@
+// Package compliance checks a notice contains everything required by POFA Schedule 4 and the code of practice before
+// it is sent. Rules are grouped into version tagged rule sets so a report always records which requirements were used.
+package compliance
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/transfer360/go-transfer360/charge"
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
+var ErrUnknownRuleSet = errors.New("unknown compliance rule set")
+
+// Status - outcome of a single rule
+type Status string
+
+const (
+	Pass          Status = "pass"
+	Fail          Status = "fail"
+	NotApplicable Status = "not_applicable"
+)
+
+// IssuerDetails - creditor information printed on the issuer's notices, configured per issuer
+type IssuerDetails struct {
+	CreditorName    string   `json:"creditor_name"`
+	CreditorAddress string   `json:"creditor_address"`
+	PaymentMethods  []string `json:"payment_methods"`
+	// AppealRoute - how to appeal to the creditor, e.g. a web address
+	AppealRoute string `json:"appeal_route"`
+	// IndependentAppealService - the independent appeals service, e.g. POPLA or IAS
+	IndependentAppealService string `json:"independent_appeal_service"`
+	// NoticeWording - the text printed on the issuer's notices, checked for the keeper liability warning and for
+	// wording relying on liability the jurisdiction does not have
+	NoticeWording string `json:"notice_wording"`
+}
+
+// Facts - what is known about a notice, filled in by the notice being checked
+type Facts struct {
+	SearchReference       string
+	ContraventionDateTime string
+	PeriodStart           string
+	PeriodEnd             string
+	RelevantLand          string
+	Charge                *charge.Schedule
+	ChargeCaps            charge.Caps
+	Jurisdiction          postcode.Jurisdiction
+	// ReliesOnKeeperLiability - the notice will be used to hold the keeper or hirer liable
+	ReliesOnKeeperLiability bool
+	Issuer                  IssuerDetails
+}
+
+// Rule - a single requirement, Check returns the outcome and a reason for anything other than a pass
+type Rule struct {
+	ID          string
+	Description string
+	// Reference - where the requirement comes from, e.g. "POFA Sch4 para 9(2)(a)"
+	Reference string
+	Check     func(f Facts) (Status, string)
+}
+
+// RuleSet - a versioned group of rules
+type RuleSet struct {
+	Version     string
+	Description string
+	Rules       []Rule
+}
+
+// Result - the outcome of one rule
+type Result struct {
+	RuleID      string `json:"rule_id"`
+	Description string `json:"description"`
+	Reference   string `json:"reference"`
+	Status      Status `json:"status"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+// Report - the outcome of checking a notice against a rule set
+type Report struct {
+	RuleSetVersion  string    `json:"rule_set_version"`
+	SearchReference string    `json:"sref"`
+	CheckedAt       time.Time `json:"checked_at"`
+	Passed          bool      `json:"passed"`
+	Results         []Result  `json:"results"`
+}
+
+// Failures returns the results of the rules which failed
+func (r Report) Failures() []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if res.Status == Fail {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Evaluate checks the facts against every rule in the set
+func (rs RuleSet) Evaluate(f Facts) Report {
+
+	report := Report{
+		RuleSetVersion:  rs.Version,
+		SearchReference: f.SearchReference,
+		CheckedAt:       time.Now(),
+		Passed:          true,
+	}
+
+	for _, rule := range rs.Rules {
+		status, detail := rule.Check(f)
+		if status == Fail {
+			report.Passed = false
+		}
+		report.Results = append(report.Results, Result{
+			RuleID:      rule.ID,
+			Description: rule.Description,
+			Reference:   rule.Reference,
+			Status:      status,
+			Detail:      detail,
+		})
+	}
+
+	return report
+}
+
+var (
+	ruleSetsMu sync.RWMutex
+	ruleSets   = map[string]RuleSet{}
+)
+
+// Register makes a rule set available to Get, replacing any with the same version
+func Register(rs RuleSet) {
+	ruleSetsMu.Lock()
+	defer ruleSetsMu.Unlock()
+	ruleSets[rs.Version] = rs
+}
+
+// Get returns the rule set with the given version
+func Get(version string) (RuleSet, error) {
+	ruleSetsMu.RLock()
+	defer ruleSetsMu.RUnlock()
+
+	rs, ok := ruleSets[version]
+	if !ok {
+		return RuleSet{}, fmt.Errorf("%w [%s]", ErrUnknownRuleSet, version)
+	}
+	return rs, nil
+}
+
+// Versions returns the registered rule set versions in order
+func Versions() []string {
+	ruleSetsMu.RLock()
+	defer ruleSetsMu.RUnlock()
+
+	var v []string
+	for version := range ruleSets {
+		v = append(v, version)
+	}
+	sort.Strings(v)
+	return v
+}
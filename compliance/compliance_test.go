@@ -0,0 +1,124 @@
+package compliance
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/transfer360/go-transfer360/charge"
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
+func compliantFacts() Facts {
+	return Facts{
+		SearchReference:         "SREF1",
+		ContraventionDateTime:   "2025-06-02T10:00:00Z",
+		PeriodStart:             "2025-06-02T09:00:00Z",
+		PeriodEnd:               "2025-06-02T10:00:00Z",
+		RelevantLand:            "Station Road Car Park",
+		Charge:                  &charge.Schedule{FullAmount: 10000, DiscountAmount: 6000, DiscountDays: 14},
+		ChargeCaps:              charge.DefaultCaps,
+		Jurisdiction:            postcode.EnglandAndWales,
+		ReliesOnKeeperLiability: true,
+		Issuer: IssuerDetails{
+			CreditorName:             "Example Parking Ltd",
+			CreditorAddress:          "1 High Street, Leeds LS1 1AA",
+			PaymentMethods:           []string{"card", "bank transfer"},
+			AppealRoute:              "https://example.com/appeal",
+			IndependentAppealService: "POPLA",
+			NoticeWording:            "If the charge is not paid within 28 days the creditor has the right to recover any unpaid amount from the keeper.",
+		},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+
+	rs, err := Get(Latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(f *Facts)
+		rule   string
+		status Status
+		also   []string // further rules the change is expected to fail
+	}{
+		{"compliant", func(f *Facts) {}, "", Pass, nil},
+		{"missing contravention", func(f *Facts) { f.ContraventionDateTime = "" }, "contravention_details", Fail, nil},
+		{"missing period", func(f *Facts) { f.PeriodEnd = "" }, "period_of_parking", Fail, nil},
+		{"missing relevant land", func(f *Facts) { f.RelevantLand = " " }, "relevant_land", Fail, nil},
+		{"missing creditor address", func(f *Facts) { f.Issuer.CreditorAddress = "" }, "creditor_identity", Fail, nil},
+		{"no charge", func(f *Facts) { f.Charge = nil }, "amounts", Fail, []string{"discount_offered"}},
+		{"charge over cap", func(f *Facts) { f.Charge = &charge.Schedule{FullAmount: 15000, DiscountAmount: 6000, DiscountDays: 14} }, "amounts", Fail, nil},
+		{"no payment methods", func(f *Facts) { f.Issuer.PaymentMethods = nil }, "payment_methods", Fail, nil},
+		{"no appeal route", func(f *Facts) { f.Issuer.AppealRoute = "" }, "appeal_route", Fail, nil},
+		{"no keeper warning", func(f *Facts) { f.Issuer.NoticeWording = "Please pay." }, "keeper_liability_warning", Fail, nil},
+		{"keeper warning not needed", func(f *Facts) { f.ReliesOnKeeperLiability = false }, "keeper_liability_warning", NotApplicable, nil},
+		{"keeper liability in Scotland", func(f *Facts) { f.Jurisdiction = postcode.Scotland }, "jurisdiction_wording", Fail, nil},
+		{"no discount", func(f *Facts) { f.Charge = &charge.Schedule{FullAmount: 10000} }, "discount_offered", Fail, nil},
+		{"discount period shorter than caps", func(f *Facts) { f.ChargeCaps = charge.Caps{MinDiscountDays: 21} }, "discount_offered", Fail, []string{"amounts"}},
+		{"no independent appeals", func(f *Facts) { f.Issuer.IndependentAppealService = "" }, "independent_appeal", Fail, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			f := compliantFacts()
+			tt.change(&f)
+			report := rs.Evaluate(f)
+
+			if report.RuleSetVersion != Latest || report.SearchReference != "SREF1" {
+				t.Errorf("unexpected report header %+v", report)
+			}
+			if len(report.Results) != len(rs.Rules) {
+				t.Fatalf("got %d results for %d rules", len(report.Results), len(rs.Rules))
+			}
+
+			for _, r := range report.Results {
+				want := Pass
+				if r.RuleID == tt.rule {
+					want = tt.status
+				} else if r.RuleID == "keeper_liability_warning" && !f.ReliesOnKeeperLiability {
+					want = NotApplicable
+				} else if r.RuleID == "keeper_liability_warning" && f.Jurisdiction != postcode.EnglandAndWales {
+					want = NotApplicable
+				} else if slices.Contains(tt.also, r.RuleID) {
+					want = Fail
+				}
+				if r.Status != want {
+					t.Errorf("%s: got %s (%s), want %s", r.RuleID, r.Status, r.Detail, want)
+				}
+			}
+
+			if report.Passed != (tt.status != Fail) {
+				t.Errorf("report passed %v with failures %v", report.Passed, report.Failures())
+			}
+		})
+	}
+}
+
+func TestRuleSetVersions(t *testing.T) {
+
+	pofa, err := Get(POFA2012V1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the POFA rule set does not include the code of practice rules
+	f := compliantFacts()
+	f.Issuer.IndependentAppealService = ""
+	if report := pofa.Evaluate(f); !report.Passed {
+		t.Errorf("unexpected failures %v", report.Failures())
+	}
+
+	if _, err = Get("unknown"); !errors.Is(err, ErrUnknownRuleSet) {
+		t.Errorf("got %v, want %v", err, ErrUnknownRuleSet)
+	}
+
+	versions := Versions()
+	if len(versions) < 2 || versions[0] != POFA2012V1 || versions[1] != CodeOfPractice2024V1 {
+		t.Errorf("unexpected versions %v", versions)
+	}
+}
@@ -0,0 +1,200 @@
+package compliance
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/transfer360/go-transfer360/jurisdiction"
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
+const (
+	// POFA2012V1 - the notice to keeper requirements of POFA Schedule 4 paragraph 9
+	POFA2012V1 = "pofa-2012-sch4-v1"
+	// CodeOfPractice2024V1 - POFA2012V1 plus the single code of practice requirements for discounts and appeals
+	CodeOfPractice2024V1 = "sppc-2024-v1"
+	// Latest - the rule set used when a version is not given
+	Latest = CodeOfPractice2024V1
+)
+
+// codeOfPractice2024DiscountDays - the 2024 code requires the discount to be available for at least 14 days
+const codeOfPractice2024DiscountDays = 14
+
+// keeperWarning - the paragraph 9(2)(f) warning that the creditor may recover the charge from the keeper
+var keeperWarning = regexp.MustCompile(`(?is)right\s+to\s+recover.{0,200}\bkeeper\b`)
+
+func present(value string, missing string) (Status, string) {
+	if len(strings.TrimSpace(value)) == 0 {
+		return Fail, missing
+	}
+	return Pass, ""
+}
+
+var pofaRules = []Rule{
+	{
+		ID:          "search_reference",
+		Description: "notice has a search reference",
+		Reference:   "Transfer360",
+		Check: func(f Facts) (Status, string) {
+			return present(f.SearchReference, "search reference is missing")
+		},
+	},
+	{
+		ID:          "contravention_details",
+		Description: "date and time of the contravention",
+		Reference:   "POFA Sch4 para 9(2)(a)",
+		Check: func(f Facts) (Status, string) {
+			if !govalidator.IsRFC3339(f.ContraventionDateTime) {
+				return Fail, "contravention date time is missing or not RFC3339"
+			}
+			return Pass, ""
+		},
+	},
+	{
+		ID:          "period_of_parking",
+		Description: "period of parking, from entry and exit or observation times",
+		Reference:   "POFA Sch4 para 9(2)(a)",
+		Check: func(f Facts) (Status, string) {
+			if len(f.PeriodStart) == 0 || len(f.PeriodEnd) == 0 {
+				return Fail, "entry and exit or observation from and to times are required"
+			}
+			return Pass, ""
+		},
+	},
+	{
+		ID:          "relevant_land",
+		Description: "the relevant land on which the vehicle was parked",
+		Reference:   "POFA Sch4 para 9(2)(a)",
+		Check: func(f Facts) (Status, string) {
+			return present(f.RelevantLand, "relevant land is missing")
+		},
+	},
+	{
+		ID:          "creditor_identity",
+		Description: "name and address of the creditor",
+		Reference:   "POFA Sch4 para 9(2)(h)",
+		Check: func(f Facts) (Status, string) {
+			if len(strings.TrimSpace(f.Issuer.CreditorName)) == 0 || len(strings.TrimSpace(f.Issuer.CreditorAddress)) == 0 {
+				return Fail, "creditor name and address are required"
+			}
+			return Pass, ""
+		},
+	},
+	{
+		ID:          "amounts",
+		Description: "the parking charge and any discount",
+		Reference:   "POFA Sch4 para 9(2)(c),(d)",
+		Check: func(f Facts) (Status, string) {
+			if f.Charge == nil {
+				return Fail, "charge schedule is missing"
+			}
+			if err := f.Charge.Validate(f.ChargeCaps); err != nil {
+				return Fail, err.Error()
+			}
+			return Pass, ""
+		},
+	},
+	{
+		ID:          "payment_methods",
+		Description: "how the charge can be paid",
+		Reference:   "POFA Sch4 para 9(2)(h)",
+		Check: func(f Facts) (Status, string) {
+			if len(f.Issuer.PaymentMethods) == 0 {
+				return Fail, "no payment methods configured for the issuer"
+			}
+			return Pass, ""
+		},
+	},
+	{
+		ID:          "appeal_route",
+		Description: "how to appeal to the creditor",
+		Reference:   "POFA Sch4 para 9(2)(g)",
+		Check: func(f Facts) (Status, string) {
+			return present(f.Issuer.AppealRoute, "no appeal route configured for the issuer")
+		},
+	},
+	{
+		ID:          "keeper_liability_warning",
+		Description: "warning that the keeper may be liable if the driver is not named",
+		Reference:   "POFA Sch4 para 9(2)(f)",
+		Check: func(f Facts) (Status, string) {
+			if !f.ReliesOnKeeperLiability || (len(f.Jurisdiction) > 0 && f.Jurisdiction != postcode.EnglandAndWales) {
+				return NotApplicable, ""
+			}
+			if len(f.Issuer.NoticeWording) == 0 {
+				return Fail, "notice wording was not supplied so the keeper warning cannot be checked"
+			}
+			if !keeperWarning.MatchString(f.Issuer.NoticeWording) {
+				return Fail, "notice wording does not warn that the creditor has the right to recover from the keeper"
+			}
+			return Pass, ""
+		},
+	},
+	{
+		ID:          "jurisdiction_wording",
+		Description: "no wording relying on liability which does not exist in the jurisdiction",
+		Reference:   "POFA s.65, Schedule 4 extends to England and Wales only",
+		Check: func(f Facts) (Status, string) {
+			j := f.Jurisdiction
+			if len(j) == 0 {
+				j = postcode.EnglandAndWales
+			}
+			rules, err := jurisdiction.RulesFor(j)
+			if err != nil {
+				return Fail, err.Error()
+			}
+			if warnings := rules.CheckWording(f.Issuer.NoticeWording); len(warnings) > 0 {
+				return Fail, strings.Join(warnings, "; ")
+			}
+			if f.ReliesOnKeeperLiability && !rules.KeeperLiability {
+				return Fail, "keeper liability is relied on but does not exist in " + rules.Name()
+			}
+			return Pass, ""
+		},
+	},
+}
+
+// codeOfPracticeRules returns the code of practice rules, minDiscountDays is the shortest discount period the code
+// allows, a longer period required by the notice's charge caps is used instead
+func codeOfPracticeRules(minDiscountDays int) []Rule {
+	return []Rule{
+		{
+			ID:          "discount_offered",
+			Description: fmt.Sprintf("a discount for payment within %d days", minDiscountDays),
+			Reference:   "Private Parking Code of Practice 2024 Annex C",
+			Check: func(f Facts) (Status, string) {
+				if f.Charge == nil || f.Charge.DiscountAmount == 0 {
+					return Fail, "no discount is offered"
+				}
+				days := max(minDiscountDays, f.ChargeCaps.MinDiscountDays)
+				if f.Charge.DiscountDays < days {
+					return Fail, fmt.Sprintf("discount is offered for %d days, at least %d are required", f.Charge.DiscountDays, days)
+				}
+				return Pass, ""
+			},
+		},
+		{
+			ID:          "independent_appeal",
+			Description: "details of the independent appeals service",
+			Reference:   "Private Parking Code of Practice 2024 section 11",
+			Check: func(f Facts) (Status, string) {
+				return present(f.Issuer.IndependentAppealService, "no independent appeals service configured for the issuer")
+			},
+		},
+	}
+}
+
+func init() {
+	Register(RuleSet{
+		Version:     POFA2012V1,
+		Description: "Protection of Freedoms Act 2012 Schedule 4 notice to keeper",
+		Rules:       pofaRules,
+	})
+	Register(RuleSet{
+		Version:     CodeOfPractice2024V1,
+		Description: "Protection of Freedoms Act 2012 Schedule 4 and Private Parking Code of Practice 2024",
+		Rules:       append(append([]Rule{}, pofaRules...), codeOfPracticeRules(codeOfPractice2024DiscountDays)...),
+	})
+}
@@ -0,0 +1,114 @@
+// Package country normalises free text country names and codes to ISO 3166-1 alpha-2 codes
+package country
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownCountry - error raised when a country name or code cannot be matched to an ISO 3166-1 code
+var ErrUnknownCountry = errors.New("unknown country")
+
+// UnitedKingdom - ISO 3166-1 alpha-2 code for the United Kingdom
+const UnitedKingdom = "GB"
+
+type info struct {
+	Name   string
+	Alpha3 string
+}
+
+var countries = map[string]info{
+	"AD": {"Andorra", "AND"}, "AE": {"United Arab Emirates", "ARE"}, "AL": {"Albania", "ALB"},
+	"AT": {"Austria", "AUT"}, "AU": {"Australia", "AUS"}, "BA": {"Bosnia and Herzegovina", "BIH"},
+	"BE": {"Belgium", "BEL"}, "BG": {"Bulgaria", "BGR"}, "BY": {"Belarus", "BLR"}, "CA": {"Canada", "CAN"},
+	"CH": {"Switzerland", "CHE"}, "CN": {"China", "CHN"}, "CY": {"Cyprus", "CYP"}, "CZ": {"Czechia", "CZE"},
+	"DE": {"Germany", "DEU"}, "DK": {"Denmark", "DNK"}, "EE": {"Estonia", "EST"}, "ES": {"Spain", "ESP"},
+	"FI": {"Finland", "FIN"}, "FR": {"France", "FRA"}, "GB": {"United Kingdom", "GBR"},
+	"GG": {"Guernsey", "GGY"}, "GI": {"Gibraltar", "GIB"}, "GR": {"Greece", "GRC"}, "HR": {"Croatia", "HRV"},
+	"HU": {"Hungary", "HUN"}, "IE": {"Ireland", "IRL"}, "IM": {"Isle of Man", "IMN"}, "IN": {"India", "IND"},
+	"IS": {"Iceland", "ISL"}, "IT": {"Italy", "ITA"}, "JE": {"Jersey", "JEY"}, "LI": {"Liechtenstein", "LIE"},
+	"LT": {"Lithuania", "LTU"}, "LU": {"Luxembourg", "LUX"}, "LV": {"Latvia", "LVA"}, "MC": {"Monaco", "MCO"},
+	"MD": {"Moldova", "MDA"}, "ME": {"Montenegro", "MNE"}, "MK": {"North Macedonia", "MKD"},
+	"MT": {"Malta", "MLT"}, "NL": {"Netherlands", "NLD"}, "NO": {"Norway", "NOR"}, "NZ": {"New Zealand", "NZL"},
+	"PK": {"Pakistan", "PAK"}, "PL": {"Poland", "POL"}, "PT": {"Portugal", "PRT"}, "RO": {"Romania", "ROU"},
+	"RS": {"Serbia", "SRB"}, "SE": {"Sweden", "SWE"}, "SI": {"Slovenia", "SVN"}, "SK": {"Slovakia", "SVK"},
+	"SM": {"San Marino", "SMR"}, "TR": {"Turkey", "TUR"}, "UA": {"Ukraine", "UKR"}, "US": {"United States", "USA"},
+	"ZA": {"South Africa", "ZAF"},
+}
+
+// aliases - common names and abbreviations seen in lease company data, keys are upper case with punctuation removed
+var aliases = map[string]string{
+	"UK": "GB", "GREAT BRITAIN": "GB", "BRITAIN": "GB", "ENGLAND": "GB", "SCOTLAND": "GB",
+	"WALES": "GB", "NORTHERN IRELAND": "GB", "UNITED KINGDOM OF GREAT BRITAIN AND NORTHERN IRELAND": "GB",
+	"ROI": "IE", "EIRE": "IE", "REPUBLIC OF IRELAND": "IE", "IRELAND REPUBLIC": "IE", "SOUTHERN IRELAND": "IE",
+	"HOLLAND": "NL", "THE NETHERLANDS": "NL", "DEUTSCHLAND": "DE", "ESPANA": "ES", "ESPAÑA": "ES",
+	"ITALIA": "IT", "CZECH REPUBLIC": "CZ", "USA": "US", "UNITED STATES OF AMERICA": "US", "AMERICA": "US",
+	"UAE": "AE", "TURKIYE": "TR", "TÜRKIYE": "TR", "MACEDONIA": "MK",
+}
+
+var lookup = map[string]string{}
+
+func init() {
+	for code, c := range countries {
+		lookup[code] = code
+		lookup[c.Alpha3] = code
+		lookup[strings.ToUpper(c.Name)] = code
+	}
+	for alias, code := range aliases {
+		lookup[alias] = code
+	}
+}
+
+// Normalise returns the ISO 3166-1 alpha-2 code for a country name, alpha-2 or alpha-3 code, or common alias such
+// as "ROI" or "Holland". An empty country is treated as the United Kingdom.
+func Normalise(name string) (string, error) {
+
+	key := clean(name)
+	if len(key) == 0 {
+		return UnitedKingdom, nil
+	}
+
+	if code, ok := lookup[key]; ok {
+		return code, nil
+	}
+
+	if code, ok := lookup[strings.TrimPrefix(key, "THE ")]; ok {
+		return code, nil
+	}
+
+	return "", fmt.Errorf("%w [%s]", ErrUnknownCountry, name)
+}
+
+// Name returns the English short name for an alpha-2 code, or the code itself if it is not known
+func Name(code string) string {
+	if c, ok := countries[strings.ToUpper(code)]; ok {
+		return c.Name
+	}
+	return code
+}
+
+// IsUK returns true if the name or code refers to the United Kingdom, an empty country is treated as the UK
+func IsUK(name string) bool {
+	code, err := Normalise(name)
+	return err == nil && code == UnitedKingdom
+}
+
+// IsUKJurisdiction returns false for countries outside the jurisdiction of the UK courts. The Crown Dependencies
+// (Isle of Man, Jersey and Guernsey) are outside UK jurisdiction even though they use UK style postcodes.
+func IsUKJurisdiction(code string) bool {
+	return strings.ToUpper(code) == UnitedKingdom
+}
+
+func clean(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ',', '(', ')', '\'':
+			return -1
+		case '-', '_':
+			return ' '
+		}
+		return r
+	}, strings.ToUpper(name))
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,103 @@
+package country
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalise(t *testing.T) {
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", "GB"},
+		{"  ", "GB"},
+		{"GB", "GB"},
+		{"gbr", "GB"},
+		{"UK", "GB"},
+		{"U.K.", "GB"},
+		{"United Kingdom", "GB"},
+		{"Northern Ireland", "GB"},
+		{"Republic of Ireland", "IE"},
+		{"republic of ireland", "IE"},
+		{"ROI", "IE"},
+		{"R.O.I.", "IE"},
+		{"Eire", "IE"},
+		{"Ireland", "IE"},
+		{"IRL", "IE"},
+		{"The Netherlands", "NL"},
+		{"Holland", "NL"},
+		{"Isle of Man", "IM"},
+		{"Jersey", "JE"},
+		{"Guernsey", "GG"},
+		{"the Isle-of-Man", "IM"},
+		{"Bosnia and Herzegovina", "BA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalise(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, name := range []string{"Atlantis", "XX", "Irish Republic of"} {
+		if _, err := Normalise(name); !errors.Is(err, ErrUnknownCountry) {
+			t.Errorf("Normalise(%q) got %v, want %v", name, err, ErrUnknownCountry)
+		}
+	}
+}
+
+func TestIsUK(t *testing.T) {
+
+	tests := map[string]bool{
+		"":                    true,
+		"GB":                  true,
+		"Scotland":            true,
+		"Republic of Ireland": false,
+		"Jersey":              false,
+		"Atlantis":            false,
+	}
+
+	for name, want := range tests {
+		if got := IsUK(name); got != want {
+			t.Errorf("IsUK(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestIsUKJurisdiction(t *testing.T) {
+
+	tests := map[string]bool{
+		"GB": true,
+		"gb": true,
+		"IE": false,
+		"IM": false,
+		"JE": false,
+		"GG": false,
+		"GI": false,
+		"":   false,
+	}
+
+	for code, want := range tests {
+		if got := IsUKJurisdiction(code); got != want {
+			t.Errorf("IsUKJurisdiction(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+
+	if got := Name("ie"); got != "Ireland" {
+		t.Errorf("got %q, want Ireland", got)
+	}
+	if got := Name("XX"); got != "XX" {
+		t.Errorf("got %q, want XX", got)
+	}
+}
@@ -0,0 +1,182 @@
+// Package evidence attaches ANPR photos, warden images and other evidence files to a notice. Files are checked by
+// their content rather than their name, limited in size and hashed so the notice records exactly what was uploaded.
+package evidence
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrUnsupportedType = errors.New("evidence must be a JPEG, PNG or PDF file")
+var ErrTooLarge = errors.New("evidence is too large")
+var ErrTooMany = errors.New("too many evidence files")
+var ErrEmpty = errors.New("evidence file is empty")
+var ErrUpload = errors.New("uploading evidence")
+
+// Endpoint - the api path evidence is posted to
+const Endpoint = "/notice/evidence"
+
+// Supported content types, as returned by http.DetectContentType
+const (
+	JPEG = "image/jpeg"
+	PNG  = "image/png"
+	PDF  = "application/pdf"
+)
+
+var supported = map[string]bool{JPEG: true, PNG: true, PDF: true}
+
+// Limits - how much evidence can be attached to one notice
+type Limits struct {
+	MaxFileSize  int64
+	MaxTotalSize int64
+	MaxFiles     int
+}
+
+// DefaultLimits - 10MB a file, 25MB and 10 files a notice
+var DefaultLimits = Limits{
+	MaxFileSize:  10 << 20,
+	MaxTotalSize: 25 << 20,
+	MaxFiles:     10,
+}
+
+// Attachment - an evidence file, only the details are sent with the notice, the content is uploaded separately
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+	data        []byte
+}
+
+// New reads an evidence file, its content type is sniffed from the data and must be JPEG, PNG or PDF
+func New(filename string, r io.Reader, limits Limits) (Attachment, error) {
+
+	a := Attachment{Filename: filepath.Base(filename)}
+
+	data, err := io.ReadAll(io.LimitReader(r, limits.MaxFileSize+1))
+	if err != nil {
+		log.Errorf("evidence.New:[%s]:%v", filename, err)
+		return a, err
+	}
+
+	if len(data) == 0 {
+		return a, fmt.Errorf("%w [%s]", ErrEmpty, a.Filename)
+	}
+	if int64(len(data)) > limits.MaxFileSize {
+		return a, fmt.Errorf("%w [%s] is more than %d bytes", ErrTooLarge, a.Filename, limits.MaxFileSize)
+	}
+
+	contentType := Sniff(data)
+	if !supported[contentType] {
+		return a, fmt.Errorf("%w [%s] is %s", ErrUnsupportedType, a.Filename, contentType)
+	}
+
+	sum := sha256.Sum256(data)
+
+	a.ContentType = contentType
+	a.Size = int64(len(data))
+	a.SHA256 = hex.EncodeToString(sum[:])
+	a.data = data
+
+	return a, nil
+}
+
+// Open reads an evidence file from disk
+func Open(path string, limits Limits) (Attachment, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	defer f.Close()
+
+	return New(path, f, limits)
+}
+
+// Sniff returns the content type of the data without any parameters, e.g. "image/png"
+func Sniff(data []byte) string {
+	contentType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	return contentType
+}
+
+// Data returns the content of the file, nil for an attachment decoded from a notice
+func (a Attachment) Data() []byte {
+	return a.data
+}
+
+// CheckLimits checks the number and total size of attachments
+func CheckLimits(attachments []Attachment, limits Limits) error {
+
+	if len(attachments) > limits.MaxFiles {
+		return fmt.Errorf("%w, %d attached, at most %d allowed", ErrTooMany, len(attachments), limits.MaxFiles)
+	}
+
+	var total int64
+	for _, a := range attachments {
+		total += a.Size
+	}
+	if total > limits.MaxTotalSize {
+		return fmt.Errorf("%w, %d bytes attached, at most %d allowed", ErrTooLarge, total, limits.MaxTotalSize)
+	}
+
+	return nil
+}
+
+// Multipart returns the multipart form posted to Endpoint for a notice's attachments, an "sref" field followed by
+// one "evidence" part per file with its SHA-256 hash in a "sha256" field of the same position
+func Multipart(sref string, attachments []Attachment) (contentType string, body *bytes.Buffer, err error) {
+
+	body = &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+
+	if err = w.WriteField("sref", sref); err != nil {
+		return "", nil, err
+	}
+
+	for _, a := range attachments {
+
+		if a.data == nil {
+			return "", nil, fmt.Errorf("%w: no content for %s", ErrUpload, a.Filename)
+		}
+
+		if err = w.WriteField("sha256", a.SHA256); err != nil {
+			return "", nil, err
+		}
+
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="evidence"; filename="%s"`, escapeQuotes(a.Filename)))
+		h.Set("Content-Type", a.ContentType)
+
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err = part.Write(a.data); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		return "", nil, err
+	}
+
+	return w.FormDataContentType(), body, nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
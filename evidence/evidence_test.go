@@ -0,0 +1,160 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+var (
+	jpegData = append([]byte{0xFF, 0xD8, 0xFF, 0xE0}, bytes.Repeat([]byte{0}, 64)...)
+	pngData  = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+	pdfData  = []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n")
+)
+
+func TestNew(t *testing.T) {
+
+	small := Limits{MaxFileSize: 32, MaxTotalSize: 64, MaxFiles: 2}
+
+	tests := []struct {
+		name        string
+		filename    string
+		data        []byte
+		limits      Limits
+		contentType string
+		wantErr     error
+	}{
+		{"jpeg", "entry.jpg", jpegData, DefaultLimits, JPEG, nil},
+		{"png", "exit.png", pngData, DefaultLimits, PNG, nil},
+		{"pdf", "pcn.pdf", pdfData, DefaultLimits, PDF, nil},
+		{"type from content not name", "photo.jpg", pngData, DefaultLimits, PNG, nil},
+		{"text", "notes.pdf", []byte("not really a pdf"), DefaultLimits, "", ErrUnsupportedType},
+		{"gif", "anim.gif", []byte("GIF89a......"), DefaultLimits, "", ErrUnsupportedType},
+		{"empty", "empty.png", nil, DefaultLimits, "", ErrEmpty},
+		{"over file size", "exit.png", pngData, small, "", ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			a, err := New(tt.filename, bytes.NewReader(tt.data), tt.limits)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			sum := sha256.Sum256(tt.data)
+			if a.ContentType != tt.contentType || a.Size != int64(len(tt.data)) || a.SHA256 != hex.EncodeToString(sum[:]) {
+				t.Errorf("unexpected attachment %+v", a)
+			}
+			if !bytes.Equal(a.Data(), tt.data) {
+				t.Errorf("data not kept")
+			}
+		})
+	}
+}
+
+func TestNewStripsDirectory(t *testing.T) {
+
+	a, err := New("/var/evidence/2025/entry.png", bytes.NewReader(pngData), DefaultLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Filename != "entry.png" {
+		t.Errorf("got filename %s", a.Filename)
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+
+	limits := Limits{MaxFileSize: 100, MaxTotalSize: 150, MaxFiles: 2}
+	file := Attachment{Size: 60}
+
+	tests := []struct {
+		name        string
+		attachments []Attachment
+		wantErr     error
+	}{
+		{"none", nil, nil},
+		{"within limits", []Attachment{file, file}, nil},
+		{"too many", []Attachment{{Size: 1}, {Size: 1}, {Size: 1}}, ErrTooMany},
+		{"total too large", []Attachment{{Size: 100}, {Size: 100}}, ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckLimits(tt.attachments, limits)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMultipart(t *testing.T) {
+
+	png, _ := New("exit.png", bytes.NewReader(pngData), DefaultLimits)
+	pdf, _ := New(`pcn "copy".pdf`, bytes.NewReader(pdfData), DefaultLimits)
+
+	contentType, body, err := Multipart("SREF1", []Attachment{png, pdf})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields []string
+	var files []Attachment
+	r := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(part)
+		if part.FormName() == "evidence" {
+			a, err := New(part.FileName(), bytes.NewReader(data), DefaultLimits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if part.Header.Get("Content-Type") != a.ContentType {
+				t.Errorf("part content type %s, sniffed %s", part.Header.Get("Content-Type"), a.ContentType)
+			}
+			files = append(files, a)
+			continue
+		}
+		fields = append(fields, part.FormName()+"="+string(data))
+	}
+
+	want := "sref=SREF1,sha256=" + png.SHA256 + ",sha256=" + pdf.SHA256
+	if strings.Join(fields, ",") != want {
+		t.Errorf("got fields %v", fields)
+	}
+	if len(files) != 2 || files[0].SHA256 != png.SHA256 || files[1].SHA256 != pdf.SHA256 || files[1].Filename != pdf.Filename {
+		t.Errorf("unexpected files %+v", files)
+	}
+
+	if _, _, err = Multipart("SREF1", []Attachment{{Filename: "decoded.png"}}); !errors.Is(err, ErrUpload) {
+		t.Errorf("attachment without content: got %v, want %v", err, ErrUpload)
+	}
+}
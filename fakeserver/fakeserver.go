@@ -0,0 +1,256 @@
+// Package fakeserver runs an in-process stand in for the Transfer360 api server, accepting notices and their evidence
+// uploads so integrations can be tested without sending anything to the real server.
+//
+//	srv := fakeserver.New()
+//	defer srv.Close()
+//
+//	err := notice.SendWith(ctx, notices.NewSender(srv.URL), "test-key")
+//	files := srv.Evidence(notice.SearchReference)
+package fakeserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/transfer360/go-transfer360/evidence"
+)
+
+// Config - how a fake server checks requests
+type Config struct {
+	// APIKey - when set, requests with a different api_key header get a 401
+	APIKey string
+	// Limits - evidence limits enforced on uploads, evidence.DefaultLimits when zero
+	Limits evidence.Limits
+}
+
+// Server - a fake api server, notices are keyed by their sref and a second notice with the same sref gets a 409
+type Server struct {
+	*httptest.Server
+	config Config
+
+	mu             sync.RWMutex
+	notices        map[string]json.RawMessage
+	evidence       map[string][]evidence.Attachment
+	failEvidence   int
+	evidenceStatus int
+}
+
+// New starts a fake server accepting any api key with evidence.DefaultLimits
+func New() *Server {
+	return NewWithConfig(Config{})
+}
+
+// NewWithConfig starts a fake server checking requests against the config
+func NewWithConfig(config Config) *Server {
+
+	if config.Limits == (evidence.Limits{}) {
+		config.Limits = evidence.DefaultLimits
+	}
+
+	s := &Server{
+		config:   config,
+		notices:  map[string]json.RawMessage{},
+		evidence: map[string][]evidence.Attachment{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(evidence.Endpoint, s.handleEvidence)
+	mux.HandleFunc("/notice/", s.handleNotice)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// FailEvidence makes the next n evidence uploads fail with the given status, to test retrying with UploadEvidence
+func (s *Server) FailEvidence(n int, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failEvidence = n
+	s.evidenceStatus = status
+}
+
+// Notice returns the JSON of the notice received with the sref
+func (s *Server) Notice(sref string) (json.RawMessage, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n, ok := s.notices[sref]
+	return n, ok
+}
+
+// Evidence returns the evidence files uploaded for the sref, Data returns their content
+func (s *Server) Evidence(sref string) []evidence.Attachment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]evidence.Attachment{}, s.evidence[sref]...)
+}
+
+func (s *Server) authorised(w http.ResponseWriter, r *http.Request) bool {
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+
+	key := r.Header.Get("api_key")
+	if len(key) == 0 || (len(s.config.APIKey) > 0 && key != s.config.APIKey) {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) handleNotice(w http.ResponseWriter, r *http.Request) {
+
+	if !s.authorised(w, r) {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n := struct {
+		Sref string `json:"sref"`
+	}{}
+	if err = json.Unmarshal(body, &n); err != nil || len(n.Sref) == 0 {
+		http.Error(w, "invalid notice", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.notices[n.Sref]; exists {
+		http.Error(w, "notice already exists", http.StatusConflict)
+		return
+	}
+	s.notices[n.Sref] = body
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`{"status":"ok"}`))
+}
+
+func (s *Server) handleEvidence(w http.ResponseWriter, r *http.Request) {
+
+	if !s.authorised(w, r) {
+		return
+	}
+
+	s.mu.Lock()
+	if s.failEvidence > 0 {
+		s.failEvidence--
+		s.mu.Unlock()
+		http.Error(w, "evidence upload failed", s.evidenceStatus)
+		return
+	}
+	s.mu.Unlock()
+
+	// room for the multipart headers on top of the files
+	r.Body = http.MaxBytesReader(w, r.Body, s.config.Limits.MaxTotalSize+(1<<20))
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var sref string
+	var hashes []string
+	var files []evidence.Attachment
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch part.FormName() {
+		case "sref":
+			sref, err = readField(part)
+		case "sha256":
+			var h string
+			h, err = readField(part)
+			hashes = append(hashes, h)
+		case "evidence":
+			var a evidence.Attachment
+			a, err = evidence.New(part.FileName(), part, s.config.Limits)
+			if err == nil && a.ContentType != part.Header.Get("Content-Type") {
+				err = fmt.Errorf("%s sent as %s but is %s", a.Filename, part.Header.Get("Content-Type"), a.ContentType)
+			}
+			files = append(files, a)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err = evidence.CheckLimits(files, s.config.Limits); err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if len(hashes) != len(files) {
+		http.Error(w, "one sha256 field is required for each evidence file", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, ok := s.notices[sref]
+	if !ok {
+		http.Error(w, "notice not found", http.StatusNotFound)
+		return
+	}
+
+	recorded := struct {
+		Evidence []evidence.Attachment `json:"evidence"`
+	}{}
+	_ = json.Unmarshal(body, &recorded)
+	inNotice := map[string]bool{}
+	for _, a := range recorded.Evidence {
+		inNotice[a.SHA256] = true
+	}
+
+	for i, a := range files {
+		if a.SHA256 != hashes[i] {
+			http.Error(w, fmt.Sprintf("sha256 of %s does not match", a.Filename), http.StatusBadRequest)
+			return
+		}
+		if !inNotice[a.SHA256] {
+			http.Error(w, fmt.Sprintf("%s is not recorded in the notice", a.Filename), http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.evidence[sref] = append(s.evidence[sref], files...)
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`{"status":"ok"}`))
+}
+
+func readField(part *multipart.Part) (string, error) {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(part, 1024)); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
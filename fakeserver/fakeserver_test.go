@@ -0,0 +1,199 @@
+package fakeserver_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/transfer360/go-transfer360/evidence"
+	"github.com/transfer360/go-transfer360/fakeserver"
+	"github.com/transfer360/go-transfer360/notices"
+)
+
+var (
+	pngData = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1}, 64)...)
+	pdfData = []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n")
+)
+
+// testNotice - a notice with evidence, the api server only needs its sref
+type testNotice struct {
+	Sref     string                `json:"sref"`
+	Evidence []evidence.Attachment `json:"evidence,omitempty"`
+}
+
+func (n *testNotice) Type() notices.Type { return notices.TypeByelaw }
+func (n *testNotice) Endpoint() string   { return "/notice/byelaw" }
+func (n *testNotice) Validate() error    { return nil }
+
+func (n *testNotice) EvidenceFiles() (string, []evidence.Attachment) {
+	return n.Sref, n.Evidence
+}
+
+func newNotice(t *testing.T, sref string) *testNotice {
+	t.Helper()
+
+	n := &testNotice{Sref: sref}
+	for name, data := range map[string][]byte{"exit.png": pngData, "pcn.pdf": pdfData} {
+		a, err := evidence.New(name, bytes.NewReader(data), evidence.DefaultLimits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n.Evidence = append(n.Evidence, a)
+	}
+	return n
+}
+
+func TestSendWithEvidence(t *testing.T) {
+	t.Parallel()
+
+	srv := fakeserver.New()
+	defer srv.Close()
+	sender := notices.NewSender(srv.URL)
+
+	n := newNotice(t, "SREF1")
+	if err := sender.Send(context.Background(), n, "key"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, ok := srv.Notice("SREF1"); !ok {
+		t.Fatal("notice not received")
+	}
+
+	uploaded := srv.Evidence("SREF1")
+	if len(uploaded) != len(n.Evidence) {
+		t.Fatalf("got %d evidence files, want %d", len(uploaded), len(n.Evidence))
+	}
+	for i, a := range uploaded {
+		if a.SHA256 != n.Evidence[i].SHA256 || a.ContentType != n.Evidence[i].ContentType || !bytes.Equal(a.Data(), n.Evidence[i].Data()) {
+			t.Errorf("evidence %d differs, got %+v want %+v", i, a, n.Evidence[i])
+		}
+	}
+
+	if err := sender.Send(context.Background(), newNotice(t, "SREF1"), "key"); !errors.Is(err, notices.ErrNoticeAlreadyExists) {
+		t.Errorf("second send: got %v, want %v", err, notices.ErrNoticeAlreadyExists)
+	}
+}
+
+func TestEvidenceUploadRetry(t *testing.T) {
+	t.Parallel()
+
+	srv := fakeserver.New()
+	defer srv.Close()
+	sender := notices.NewSender(srv.URL)
+
+	srv.FailEvidence(1, http.StatusServiceUnavailable)
+
+	n := newNotice(t, "SREF1")
+	err := sender.Send(context.Background(), n, "key")
+	if !errors.Is(err, notices.ErrEvidenceNotUploaded) || !errors.Is(err, evidence.ErrUpload) {
+		t.Fatalf("got %v, want %v", err, notices.ErrEvidenceNotUploaded)
+	}
+	if _, ok := srv.Notice("SREF1"); !ok {
+		t.Fatal("notice should have been sent")
+	}
+
+	if err = sender.UploadEvidence(context.Background(), n, "key"); err != nil {
+		t.Fatalf("retry: unexpected error %v", err)
+	}
+	if len(srv.Evidence("SREF1")) != 2 {
+		t.Fatalf("evidence not uploaded on retry")
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	t.Parallel()
+
+	srv := fakeserver.NewWithConfig(fakeserver.Config{APIKey: "right"})
+	defer srv.Close()
+
+	err := notices.NewSender(srv.URL).Send(context.Background(), &testNotice{Sref: "SREF1"}, "wrong")
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("got %v, want a 401", err)
+	}
+}
+
+type part struct {
+	filename    string
+	contentType string
+	data        []byte
+	sha256      string
+}
+
+// upload posts a hand built evidence form so the server's checks can be tested with bad data
+func upload(srv *fakeserver.Server, sref string, parts ...part) error {
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	_ = w.WriteField("sref", sref)
+	for _, p := range parts {
+		_ = w.WriteField("sha256", p.sha256)
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="evidence"; filename="%s"`, p.filename))
+		h.Set("Content-Type", p.contentType)
+		pw, _ := w.CreatePart(h)
+		_, _ = pw.Write(p.data)
+	}
+	_ = w.Close()
+
+	return notices.NewSender(srv.URL).Post(context.Background(), evidence.Endpoint, w.FormDataContentType(), body, "key")
+}
+
+func TestEvidenceRejected(t *testing.T) {
+	t.Parallel()
+
+	limits := evidence.Limits{MaxFileSize: 200, MaxTotalSize: 250, MaxFiles: 2}
+	srv := fakeserver.NewWithConfig(fakeserver.Config{Limits: limits})
+	defer srv.Close()
+
+	n := newNotice(t, "SREF1")
+	if err := notices.NewSender(srv.URL).Send(context.Background(), n, "key"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	png := n.Evidence[0]
+	if png.ContentType != evidence.PNG {
+		png = n.Evidence[1]
+	}
+
+	good := part{filename: "exit.png", contentType: evidence.PNG, data: pngData, sha256: png.SHA256}
+
+	tests := []struct {
+		name  string
+		sref  string
+		parts []part
+		code  int
+	}{
+		{"sha256 mismatch", "SREF1", []part{{filename: "exit.png", contentType: evidence.PNG, data: pngData, sha256: strings.Repeat("0", 64)}}, 400},
+		{"content is not the declared type", "SREF1", []part{{filename: "exit.png", contentType: evidence.PNG, data: pdfData, sha256: png.SHA256}}, 400},
+		{"unsupported type", "SREF1", []part{{filename: "notes.txt", contentType: "text/plain", data: []byte("hello"), sha256: png.SHA256}}, 400},
+		{"file too large", "SREF1", []part{{filename: "big.png", contentType: evidence.PNG, data: append(pngData, make([]byte, 200)...), sha256: png.SHA256}}, 400},
+		{"too many files", "SREF1", []part{good, good, good}, 413},
+		{"not recorded in the notice", "SREF1", []part{{filename: "other.png", contentType: evidence.PNG, data: append(pngData, 2), sha256: ""}}, 400},
+		{"unknown notice", "SREF2", []part{good}, 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := upload(srv, tt.sref, tt.parts...)
+			if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("(%d)", tt.code)) {
+				t.Fatalf("got %v, want a %d", err, tt.code)
+			}
+		})
+	}
+
+	if len(srv.Evidence("SREF1")) != len(n.Evidence) {
+		t.Errorf("rejected evidence was stored")
+	}
+
+	if err := upload(srv, "SREF1", good); err != nil {
+		t.Fatalf("valid upload: unexpected error %v", err)
+	}
+	if len(srv.Evidence("SREF1")) != len(n.Evidence)+1 {
+		t.Errorf("valid evidence was not stored")
+	}
+}
@@ -0,0 +1,174 @@
+// Package fingerprint detects notices which have already been sent, or which overlap a stay already charged, before
+// they reach the api server. Each sent notice is recorded as a Fingerprint in a Store and new notices are checked
+// against the recent fingerprints for the same vehicle.
+package fingerprint
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/transfer360/go-transfer360/vrm"
+)
+
+var ErrLikelyDuplicate = errors.New("notice is likely a duplicate of one already sent")
+
+const NOTICE_FINGERPRINTS_COLLECTION = "notice_fingerprints"
+
+// DefaultWindow - contraventions at the same site this close together are treated as the same contravention
+const DefaultWindow = 15 * time.Minute
+
+// DefaultLookback - how far back fingerprints are checked
+const DefaultLookback = 90 * 24 * time.Hour
+
+// Fingerprint - what identifies a sent notice
+type Fingerprint struct {
+	VRM  string `json:"vrm" firestore:"vrm"`
+	Site string `json:"site" firestore:"site"`
+	// NoticeNumber - the issuer's own reference for the notice, optional
+	NoticeNumber string `json:"notice_number,omitempty" firestore:"notice_number"`
+	// Contravention, Start and End - the contravention and the stay it was part of, Start and End are the
+	// contravention time when the stay is not known
+	Contravention   time.Time `json:"contravention" firestore:"contravention"`
+	Start           time.Time `json:"start" firestore:"start"`
+	End             time.Time `json:"end" firestore:"end"`
+	SearchReference string    `json:"sref" firestore:"sref"`
+	RecordedAt      time.Time `json:"recorded_at" firestore:"recorded_at"`
+}
+
+// New returns the fingerprint of a notice, the vrm and site are normalised so differently formatted copies match
+func New(registration string, site string, noticeNumber string, contravention time.Time, start time.Time, end time.Time) Fingerprint {
+
+	if start.IsZero() || end.IsZero() {
+		start, end = contravention, contravention
+	}
+
+	return Fingerprint{
+		VRM:           vrm.Clean(registration),
+		Site:          normalise(site),
+		NoticeNumber:  normalise(noticeNumber),
+		Contravention: contravention.UTC(),
+		Start:         start.UTC(),
+		End:           end.UTC(),
+	}
+}
+
+func normalise(value string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(value)), " ")
+}
+
+// Key returns an id for the fingerprint, the same for notices with the same vehicle, site, notice number and
+// contravention time
+func (f Fingerprint) Key() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		f.VRM, f.Site, f.NoticeNumber, f.Contravention.UTC().Format(time.RFC3339),
+	}, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Reason - why a fingerprint matched one already sent
+type Reason string
+
+const (
+	// SameNoticeNumber - the issuer's notice number has already been sent for the vehicle
+	SameNoticeNumber Reason = "same_notice_number"
+	// SameContravention - the vehicle has a notice for the same site within the window of the contravention
+	SameContravention Reason = "same_contravention"
+	// OverlappingStay - the vehicle has a notice for the same site whose stay overlaps this one
+	OverlappingStay Reason = "overlapping_stay"
+)
+
+// Match - a fingerprint already sent which a new notice matched
+type Match struct {
+	Reason      Reason      `json:"reason"`
+	Fingerprint Fingerprint `json:"fingerprint"`
+}
+
+// IsDuplicate returns true for matches which mean the notice has already been sent, an overlapping stay may be a
+// separate contravention
+func (m Match) IsDuplicate() bool {
+	return m.Reason == SameNoticeNumber || m.Reason == SameContravention
+}
+
+func (m Match) String() string {
+	return fmt.Sprintf("%s with %s (%s)", m.Reason, m.Fingerprint.SearchReference, m.Fingerprint.Contravention.Format(time.RFC3339))
+}
+
+// Store - where recently sent fingerprints are kept
+type Store interface {
+	// Recent returns fingerprints for the vehicle recorded since the given time
+	Recent(ctx context.Context, vrm string, since time.Time) ([]Fingerprint, error)
+	Record(ctx context.Context, f Fingerprint) error
+}
+
+// Detector - checks notices against a Store
+type Detector struct {
+	Store Store
+	// Window - see DefaultWindow, used when zero
+	Window time.Duration
+	// Lookback - see DefaultLookback, used when zero
+	Lookback time.Duration
+}
+
+// NewDetector returns a Detector using the default window and lookback
+func NewDetector(store Store) *Detector {
+	return &Detector{Store: store, Window: DefaultWindow, Lookback: DefaultLookback}
+}
+
+// Check returns the fingerprints already sent which the notice matches, a fingerprint for another search reference
+// with the same key is always a SameContravention match
+func (d *Detector) Check(ctx context.Context, f Fingerprint) ([]Match, error) {
+
+	window := d.Window
+	if window == 0 {
+		window = DefaultWindow
+	}
+	lookback := d.Lookback
+	if lookback == 0 {
+		lookback = DefaultLookback
+	}
+
+	recent, err := d.Store.Recent(ctx, f.VRM, time.Now().Add(-lookback))
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, p := range recent {
+
+		if len(p.SearchReference) > 0 && p.SearchReference == f.SearchReference && p.Key() == f.Key() {
+			// the same notice being sent again after a failure
+			continue
+		}
+
+		switch {
+		case len(f.NoticeNumber) > 0 && f.NoticeNumber == p.NoticeNumber:
+			matches = append(matches, Match{Reason: SameNoticeNumber, Fingerprint: p})
+		case f.Site == p.Site && absDuration(f.Contravention.Sub(p.Contravention)) <= window:
+			matches = append(matches, Match{Reason: SameContravention, Fingerprint: p})
+		case f.Site == p.Site && !f.Start.After(p.End) && !p.Start.After(f.End):
+			matches = append(matches, Match{Reason: OverlappingStay, Fingerprint: p})
+		}
+	}
+
+	return matches, nil
+}
+
+// Record saves the fingerprint of a sent notice
+func (d *Detector) Record(ctx context.Context, f Fingerprint) error {
+	if f.RecordedAt.IsZero() {
+		f.RecordedAt = time.Now()
+	}
+	return d.Store.Record(ctx, f)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
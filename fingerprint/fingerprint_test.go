@@ -0,0 +1,118 @@
+package fingerprint
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDetectorCheck(t *testing.T) {
+
+	ctx := context.Background()
+	base := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+
+	sent := New("AB12 CDE", "LS1 4DY Station Road", "PCN1", base, base.Add(-time.Hour), base)
+	sent.SearchReference = "SREF1"
+
+	tests := []struct {
+		name   string
+		f      Fingerprint
+		sref   string
+		reason Reason
+	}{
+		{
+			"same notice number at another site",
+			New("ab12cde", "M1 1AA Other", "pcn1", base.Add(48*time.Hour), time.Time{}, time.Time{}),
+			"SREF2", SameNoticeNumber,
+		},
+		{
+			"same site within the window",
+			New("AB12-CDE", "ls1 4dy  station road", "PCN2", base.Add(10*time.Minute), time.Time{}, time.Time{}),
+			"SREF2", SameContravention,
+		},
+		{
+			"overlapping stay",
+			New("AB12CDE", "LS1 4DY Station Road", "PCN2", base.Add(-30*time.Minute), base.Add(-50*time.Minute), base.Add(-20*time.Minute)),
+			"SREF2", OverlappingStay,
+		},
+		{
+			"later stay at the same site",
+			New("AB12CDE", "LS1 4DY Station Road", "PCN2", base.Add(3*time.Hour), base.Add(2*time.Hour), base.Add(3*time.Hour)),
+			"SREF2", "",
+		},
+		{
+			"different vehicle",
+			New("XY12ZZZ", "LS1 4DY Station Road", "PCN1", base, time.Time{}, time.Time{}),
+			"SREF2", "",
+		},
+		{
+			"same notice sent again",
+			sent,
+			"SREF1", "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			d := NewDetector(NewMemoryStore())
+			if err := d.Record(ctx, sent); err != nil {
+				t.Fatal(err)
+			}
+
+			f := tt.f
+			f.SearchReference = tt.sref
+			matches, err := d.Check(ctx, f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tt.reason) == 0 {
+				if len(matches) > 0 {
+					t.Fatalf("unexpected matches %v", matches)
+				}
+				return
+			}
+			if len(matches) != 1 || matches[0].Reason != tt.reason {
+				t.Fatalf("got %v, want %s", matches, tt.reason)
+			}
+		})
+	}
+}
+
+func TestDetectorLookback(t *testing.T) {
+
+	ctx := context.Background()
+	base := time.Now().Add(-24 * time.Hour)
+
+	old := New("AB12CDE", "Site", "PCN1", base, time.Time{}, time.Time{})
+	old.SearchReference = "SREF1"
+	old.RecordedAt = time.Now().Add(-48 * time.Hour)
+
+	d := &Detector{Store: NewMemoryStore(), Lookback: 24 * time.Hour}
+	if err := d.Record(ctx, old); err != nil {
+		t.Fatal(err)
+	}
+
+	f := New("AB12CDE", "Site", "PCN1", base, time.Time{}, time.Time{})
+	f.SearchReference = "SREF2"
+	matches, err := d.Check(ctx, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) > 0 {
+		t.Fatalf("fingerprint recorded before the lookback matched %v", matches)
+	}
+}
+
+func TestKeyIgnoresFormatting(t *testing.T) {
+
+	at := time.Date(2025, 6, 2, 10, 0, 0, 0, time.FixedZone("BST", 3600))
+
+	a := New("ab12 cde", "ls1  4dy", "pcn1", at, time.Time{}, time.Time{})
+	b := New("AB12CDE", "LS1 4DY", "PCN1", at.UTC(), time.Time{}, time.Time{})
+
+	if a.Key() != b.Key() {
+		t.Fatalf("keys differ %s %s", a.Key(), b.Key())
+	}
+}
@@ -0,0 +1,56 @@
+package fingerprint
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
+	"time"
+)
+
+// FirestoreStore - keeps fingerprints in the notice_fingerprints collection, one document per fingerprint and search
+// reference so a notice sent again after a failure is not recorded twice. Recent needs the composite index on vrm and
+// recorded_at in firestore.indexes.json.
+type FirestoreStore struct {
+	fs *firestore.Client
+}
+
+func NewFirestoreStore(fs *firestore.Client) *FirestoreStore {
+	return &FirestoreStore{fs: fs}
+}
+
+func (s *FirestoreStore) Recent(ctx context.Context, vrm string, since time.Time) ([]Fingerprint, error) {
+
+	var recent []Fingerprint
+
+	itr := s.fs.Collection(NOTICE_FINGERPRINTS_COLLECTION).Where("vrm", "==", vrm).Where("recorded_at", ">=", since).Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			log.Errorf("FirestoreStore.Recent:[%s]:%v", vrm, err)
+			return nil, err
+		}
+
+		f := Fingerprint{}
+		if err = doc.DataTo(&f); err != nil {
+			log.Errorf("FirestoreStore.Recent:[%s]:%v", doc.Ref.ID, err)
+			return nil, err
+		}
+		recent = append(recent, f)
+	}
+
+	return recent, nil
+}
+
+func (s *FirestoreStore) Record(ctx context.Context, f Fingerprint) error {
+
+	_, err := s.fs.Collection(NOTICE_FINGERPRINTS_COLLECTION).Doc(f.Key()+"_"+f.SearchReference).Set(ctx, f)
+	if err != nil {
+		log.Errorf("FirestoreStore.Record:[%s]:%v", f.SearchReference, err)
+	}
+	return err
+}
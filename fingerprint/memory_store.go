@@ -0,0 +1,45 @@
+package fingerprint
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore - keeps fingerprints in memory, for tests and single process pipelines
+type MemoryStore struct {
+	mu           sync.RWMutex
+	fingerprints map[string][]Fingerprint
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{fingerprints: map[string][]Fingerprint{}}
+}
+
+func (s *MemoryStore) Recent(_ context.Context, vrm string, since time.Time) ([]Fingerprint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var recent []Fingerprint
+	for _, f := range s.fingerprints[vrm] {
+		if !f.RecordedAt.Before(since) {
+			recent = append(recent, f)
+		}
+	}
+	return recent, nil
+}
+
+func (s *MemoryStore) Record(_ context.Context, f Fingerprint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing := s.fingerprints[f.VRM]
+	for i, e := range existing {
+		if e.Key() == f.Key() && e.SearchReference == f.SearchReference {
+			existing[i] = f
+			return nil
+		}
+	}
+	s.fingerprints[f.VRM] = append(existing, f)
+	return nil
+}
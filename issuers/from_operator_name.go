@@ -12,15 +12,60 @@ import (
 var ErrIssuerNotFound = errors.New("issuer information not found")
 
 func FromOperatorsName(ctx context.Context, operatorName string, fs *firestore.Client) (IssuerInformation, error) {
+	iInfo, _, err := resolveOperatorName(ctx, operatorName, fs)
+	return iInfo, err
+}
+
+// resolveOperatorName tries the exact operator name, then the exact issuer name, then the normalised name and aliases,
+// then the name of one of the operator's sites, returning which of them matched
+func resolveOperatorName(ctx context.Context, operatorName string, fs *firestore.Client) (IssuerInformation, MatchPath, error) {
+
+	iInfo, err := fromOperatorNameExact(ctx, operatorName, fs)
+	if err == nil {
+		return iInfo, MatchedOperatorName, nil
+	}
+	if !errors.Is(err, ErrIssuerNotFound) {
+		return iInfo, "", err
+	}
+
+	iInfo, err = FromIssuerName(ctx, operatorName, fs)
+	if err == nil {
+		return iInfo, MatchedIssuerName, nil
+	}
+	if !errors.Is(err, ErrIssuerNotFound) {
+		return iInfo, "", err
+	}
+
+	// fall back to case, punctuation and suffix insensitive matching, including configured aliases
+	iInfo, err = FromNormalisedName(ctx, operatorName, fs)
+	if err == nil {
+		return iInfo, MatchedNormalisedName, nil
+	}
+	if !errors.Is(err, ErrIssuerNotFound) {
+		return iInfo, "", err
+	}
+
+	iInfo, err = FromSiteName(ctx, operatorName, fs)
+	if errors.Is(err, ErrSiteNotFound) {
+		return iInfo, "", fmt.Errorf("%w with name %s", ErrIssuerNotFound, operatorName)
+	}
+	if err != nil {
+		return iInfo, "", err
+	}
+	return iInfo, MatchedSiteName, nil
+}
+
+func fromOperatorNameExact(ctx context.Context, operatorName string, fs *firestore.Client) (IssuerInformation, error) {
 
 	iInfo := IssuerInformation{}
 	isserInfo := struct {
 		Issuer           string `firestore:"issuer"`
 		T360ID           string `firestore:"t360_id"`
 		SoftwareProvider int    `firestore:"software_provider"`
+		Deactivated      bool   `firestore:"deactivated"`
 	}{}
 
-	itr := fs.Collection("registered_issuers").Where("operator_name", "==", operatorName).Documents(ctx)
+	itr := fs.Collection(REGISTERED_ISSUERS_COLLECTION).Where("operator_name", "==", operatorName).Documents(ctx)
 	for {
 		doc, err := itr.Next()
 		if err != nil {
@@ -36,6 +81,9 @@ func FromOperatorsName(ctx context.Context, operatorName string, fs *firestore.C
 				if err != nil {
 					log.Errorf("FromOperatorsName:[%s]:%v", operatorName, err)
 					return iInfo, err
+				} else if isserInfo.Deactivated {
+					isserInfo.Issuer = ""
+					isserInfo.Deactivated = false
 				} else {
 					break
 				}
@@ -44,7 +92,7 @@ func FromOperatorsName(ctx context.Context, operatorName string, fs *firestore.C
 	}
 
 	if len(isserInfo.Issuer) == 0 {
-		return FromIssuerName(ctx, operatorName, fs)
+		return iInfo, fmt.Errorf("%w with operator name %s", ErrIssuerNotFound, operatorName)
 	}
 
 	iInfo.T360ID = isserInfo.T360ID
@@ -63,9 +111,10 @@ func FromIssuerName(ctx context.Context, operatorName string, fs *firestore.Clie
 		Issuer           string `firestore:"issuer"`
 		T360ID           string `firestore:"t360_id"`
 		SoftwareProvider int    `firestore:"software_provider"`
+		Deactivated      bool   `firestore:"deactivated"`
 	}{}
 
-	itr := fs.Collection("registered_issuers").Where("issuer", "==", operatorName).Documents(ctx)
+	itr := fs.Collection(REGISTERED_ISSUERS_COLLECTION).Where("issuer", "==", operatorName).Documents(ctx)
 	for {
 		doc, err := itr.Next()
 		if err != nil {
@@ -81,6 +130,9 @@ func FromIssuerName(ctx context.Context, operatorName string, fs *firestore.Clie
 				if err != nil {
 					log.Errorf("FromOperatorsName:[%s]:%v", operatorName, err)
 					return iInfo, err
+				} else if isserInfo.Deactivated {
+					isserInfo.Issuer = ""
+					isserInfo.Deactivated = false
 				} else {
 					break
 				}
@@ -46,7 +46,7 @@ func FromSearchReference(ctx context.Context, sref string, fs *firestore.Client)
 	}
 
 	if len(issuerID) == 0 {
-		return iInfo, fmt.Errorf("issuer not found with search ref [%s]", sref)
+		return iInfo, fmt.Errorf("%w with search ref [%s]", ErrIssuerNotFound, sref)
 	}
 
 	iInfo, err := GetIssuerInformationFromT360ID(ctx, issuerID, fs)
@@ -0,0 +1,138 @@
+package issuers
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/search"
+	"google.golang.org/api/iterator"
+	"time"
+)
+
+// noticeStatusCollection - where lease company responses to parking charge notices are recorded, see
+// parking_charge_notice.GetHirer
+const noticeStatusCollection = "parking_charge_notices_status_update"
+
+// firestore allows at most 30 values in an "in" query
+const maxInQueryValues = 30
+
+// OperatorActivity - searches and lease company responses for one operator in a group
+type OperatorActivity struct {
+	T360ID        string `json:"t360_id"`
+	Issuer        string `json:"issuer"`
+	Searches      int    `json:"searches"`
+	HirerVehicles int    `json:"hirer_vehicles"`
+	// LeaseCompanyResponses - hirer vehicle searches a lease company has responded to with a status update, a
+	// notice sent with no response yet is not counted
+	LeaseCompanyResponses int `json:"lease_company_responses"`
+}
+
+// GroupActivity - searches and lease company responses for every operator in a group, rolled up to group totals
+type GroupActivity struct {
+	Group                 Group              `json:"group"`
+	From                  time.Time          `json:"from"`
+	To                    time.Time          `json:"to"`
+	Operators             []OperatorActivity `json:"operators"`
+	Searches              int                `json:"searches"`
+	HirerVehicles         int                `json:"hirer_vehicles"`
+	LeaseCompanyResponses int                `json:"lease_company_responses"`
+}
+
+// GroupActivityReport counts the searches made between from and to (exclusive) by each operator in the group, how
+// many were hirer vehicles and how many of those a lease company has responded to about a notice. Searches still using the legacy
+// client.issuerid field are not counted, run migrations.NormaliseSearchIssuerID first. The searches query needs a
+// composite index on client.issuer_id and search_date.
+func GroupActivityReport(ctx context.Context, groupID string, from, to time.Time, fs *firestore.Client) (GroupActivity, error) {
+
+	group, err := GetGroup(ctx, groupID, fs)
+	if err != nil {
+		return GroupActivity{}, err
+	}
+
+	operators, err := GroupOperators(ctx, groupID, fs)
+	if err != nil {
+		return GroupActivity{}, err
+	}
+
+	report := GroupActivity{Group: group, From: from, To: to}
+	for _, op := range operators {
+
+		activity, err := operatorActivity(ctx, op, from, to, fs)
+		if err != nil {
+			log.Errorf("GroupActivityReport:[%s]:[%s]:%v", groupID, op.T360ID, err)
+			return report, err
+		}
+
+		report.Operators = append(report.Operators, activity)
+		report.Searches += activity.Searches
+		report.HirerVehicles += activity.HirerVehicles
+		report.LeaseCompanyResponses += activity.LeaseCompanyResponses
+	}
+
+	return report, nil
+}
+
+func operatorActivity(ctx context.Context, op RegisteredIssuer, from, to time.Time, fs *firestore.Client) (OperatorActivity, error) {
+
+	activity := OperatorActivity{T360ID: op.T360ID, Issuer: op.Issuer}
+
+	record := struct {
+		Sref   string `firestore:"sref"`
+		Result struct {
+			IsHirerVehicle bool `firestore:"is_hirer_vehicle"`
+		} `firestore:"result"`
+	}{}
+
+	var hirerSrefs []string
+
+	itr := fs.Collection(search.SEARCHES_COLLECTION).
+		Where("client.issuer_id", "==", op.T360ID).
+		Where("search_date", ">=", from).
+		Where("search_date", "<", to).
+		Select("sref", "result.is_hirer_vehicle").
+		Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return activity, err
+		}
+
+		record.Sref, record.Result.IsHirerVehicle = "", false
+		if err = doc.DataTo(&record); err != nil {
+			return activity, err
+		}
+
+		activity.Searches++
+		if record.Result.IsHirerVehicle {
+			activity.HirerVehicles++
+			hirerSrefs = append(hirerSrefs, record.Sref)
+		}
+	}
+
+	for start := 0; start < len(hirerSrefs); start += maxInQueryValues {
+		end := start + maxInQueryValues
+		if end > len(hirerSrefs) {
+			end = len(hirerSrefs)
+		}
+
+		responded := map[string]bool{}
+		docs, err := fs.Collection(noticeStatusCollection).Where("Sref", "in", hirerSrefs[start:end]).Select("Sref").Documents(ctx).GetAll()
+		if err != nil {
+			return activity, err
+		}
+		for _, doc := range docs {
+			if sref, err := doc.DataAt("Sref"); err == nil {
+				if s, ok := sref.(string); ok {
+					responded[s] = true
+				}
+			}
+		}
+		activity.LeaseCompanyResponses += len(responded)
+	}
+
+	return activity, nil
+}
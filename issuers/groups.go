@@ -0,0 +1,244 @@
+package issuers
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
+	"strings"
+)
+
+const ISSUER_GROUPS_COLLECTION = "issuer_groups"
+const ISSUER_SITES_COLLECTION = "issuer_sites"
+
+var ErrGroupNotFound = errors.New("issuer group not found")
+var ErrSiteNotFound = errors.New("issuer site not found")
+
+// Group - the top of the issuer hierarchy, a customer which owns one or more operators (registered issuers). An
+// operator joins or leaves a group with SetIssuerGroup.
+type Group struct {
+	GroupID string `json:"group_id" firestore:"group_id" validate:"required"`
+	Name    string `json:"name" firestore:"name" validate:"required"`
+}
+
+// Site - a car park or location run by an operator, sites are the bottom of the hierarchy and let a site or trading
+// name be resolved to the operator's registered issuer
+type Site struct {
+	SiteID  string   `json:"site_id" firestore:"site_id" validate:"required"`
+	Name    string   `json:"name" firestore:"name" validate:"required"`
+	T360ID  string   `json:"t360_id" firestore:"t360_id" validate:"required"`
+	Aliases []string `json:"aliases,omitempty" firestore:"aliases,omitempty"`
+}
+
+// SaveGroup creates or replaces a group
+func SaveGroup(ctx context.Context, g Group, fs *firestore.Client) error {
+
+	g.GroupID = strings.TrimSpace(g.GroupID)
+	g.Name = strings.TrimSpace(g.Name)
+
+	validate := validator.New()
+	if err := validate.Struct(g); err != nil {
+		return err
+	}
+
+	_, err := fs.Collection(ISSUER_GROUPS_COLLECTION).Doc(g.GroupID).Set(ctx, g)
+	if err != nil {
+		log.Errorf("SaveGroup:[%s]:%v", g.GroupID, err)
+	}
+	return err
+}
+
+// GetGroup returns the group with the given id
+func GetGroup(ctx context.Context, groupID string, fs *firestore.Client) (Group, error) {
+
+	g := Group{}
+
+	doc, err := fs.Collection(ISSUER_GROUPS_COLLECTION).Doc(groupID).Get(ctx)
+	if err != nil {
+		if doc != nil && !doc.Exists() {
+			return g, fmt.Errorf("%w [%s]", ErrGroupNotFound, groupID)
+		}
+		log.Errorf("GetGroup:[%s]:%v", groupID, err)
+		return g, err
+	}
+
+	if err = doc.DataTo(&g); err != nil {
+		log.Errorf("GetGroup:[%s]:%v", groupID, err)
+		return g, err
+	}
+
+	return g, nil
+}
+
+// GroupOf returns the group an operator belongs to
+func GroupOf(ctx context.Context, t360ID string, fs *firestore.Client) (Group, error) {
+
+	var groupID string
+
+	itr := fs.Collection(REGISTERED_ISSUERS_COLLECTION).Where("t360_id", "==", t360ID).Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			log.Errorf("GroupOf:[%s]:%v", t360ID, err)
+			return Group{}, err
+		}
+
+		ri := RegisteredIssuer{}
+		if err = doc.DataTo(&ri); err != nil {
+			log.Errorf("GroupOf:[%s]:%v", t360ID, err)
+			return Group{}, err
+		}
+		if len(ri.GroupID) > 0 {
+			groupID = ri.GroupID
+		}
+	}
+
+	if len(groupID) == 0 {
+		return Group{}, fmt.Errorf("%w for issuer %s", ErrGroupNotFound, t360ID)
+	}
+
+	return GetGroup(ctx, groupID, fs)
+}
+
+// GroupOperators returns the registered issuers belonging to a group, including deactivated ones so historic
+// searches still roll up
+func GroupOperators(ctx context.Context, groupID string, fs *firestore.Client) ([]RegisteredIssuer, error) {
+
+	var operators []RegisteredIssuer
+
+	itr := fs.Collection(REGISTERED_ISSUERS_COLLECTION).Where("group_id", "==", groupID).Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			log.Errorf("GroupOperators:[%s]:%v", groupID, err)
+			return nil, err
+		}
+
+		ri := RegisteredIssuer{}
+		if err = doc.DataTo(&ri); err != nil {
+			log.Errorf("GroupOperators:[%s]:%v", groupID, err)
+			return nil, err
+		}
+		ri.docID = doc.Ref.ID
+		operators = append(operators, ri)
+	}
+
+	return operators, nil
+}
+
+// SaveSite creates or replaces a site, the site's operator must be a registered issuer
+func SaveSite(ctx context.Context, s Site, fs *firestore.Client) error {
+
+	s.SiteID = strings.TrimSpace(s.SiteID)
+	s.Name = strings.TrimSpace(s.Name)
+	s.T360ID = strings.TrimSpace(s.T360ID)
+
+	validate := validator.New()
+	if err := validate.Struct(s); err != nil {
+		return err
+	}
+
+	operator, err := GetIssuerInformationFromT360ID(ctx, s.T360ID, fs)
+	if err != nil {
+		return err
+	}
+	if len(operator.T360ID) == 0 {
+		return fmt.Errorf("%w with t360 id %s", ErrIssuerNotFound, s.T360ID)
+	}
+
+	_, err = fs.Collection(ISSUER_SITES_COLLECTION).Doc(s.SiteID).Set(ctx, s)
+	if err != nil {
+		log.Errorf("SaveSite:[%s]:%v", s.SiteID, err)
+	}
+	return err
+}
+
+// OperatorSites returns the sites run by an operator
+func OperatorSites(ctx context.Context, t360ID string, fs *firestore.Client) ([]Site, error) {
+	return sites(ctx, fs.Collection(ISSUER_SITES_COLLECTION).Where("t360_id", "==", t360ID))
+}
+
+// FromSiteName finds the operator running a site, matching the site name or one of its aliases after normalising
+// both with NormaliseName. Sites run by deactivated operators are skipped.
+func FromSiteName(ctx context.Context, siteName string, fs *firestore.Client) (IssuerInformation, error) {
+
+	want := NormaliseName(siteName)
+	if len(want) == 0 {
+		return IssuerInformation{}, fmt.Errorf("%w with name %s", ErrSiteNotFound, siteName)
+	}
+
+	all, err := sites(ctx, fs.Collection(ISSUER_SITES_COLLECTION).Query)
+	if err != nil {
+		return IssuerInformation{}, err
+	}
+
+	issuers, err := allRegisteredIssuers(ctx, fs)
+	if err != nil {
+		log.Errorf("FromSiteName:[%s]:%v", siteName, err)
+		return IssuerInformation{}, err
+	}
+
+	for _, s := range matchingSites(want, all) {
+		operator, found := findByT360ID(s.T360ID, issuers)
+		if !found {
+			return IssuerInformation{}, fmt.Errorf("%w with t360 id %s for site %s", ErrIssuerNotFound, s.T360ID, s.SiteID)
+		}
+		if operator.Deactivated {
+			continue
+		}
+		return operator.information(), nil
+	}
+
+	return IssuerInformation{}, fmt.Errorf("%w with name %s", ErrSiteNotFound, siteName)
+}
+
+// matchingSites returns the sites whose name or one of its aliases normalises to name
+func matchingSites(name string, all []Site) []Site {
+
+	var matched []Site
+	for _, s := range all {
+		for _, n := range append([]string{s.Name}, s.Aliases...) {
+			if NormaliseName(n) == name {
+				matched = append(matched, s)
+				break
+			}
+		}
+	}
+
+	return matched
+}
+
+func sites(ctx context.Context, q firestore.Query) ([]Site, error) {
+
+	var all []Site
+
+	itr := q.Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			log.Errorf("sites:%v", err)
+			return nil, err
+		}
+
+		s := Site{}
+		if err = doc.DataTo(&s); err != nil {
+			log.Errorf("sites:[%s]:%v", doc.Ref.ID, err)
+			return nil, err
+		}
+		all = append(all, s)
+	}
+
+	return all, nil
+}
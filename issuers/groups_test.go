@@ -0,0 +1,36 @@
+package issuers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchingSites(t *testing.T) {
+
+	all := []Site{
+		{SiteID: "S1", Name: "Station Road Car Park", T360ID: "T1"},
+		{SiteID: "S2", Name: "Retail Park", T360ID: "T2", Aliases: []string{"Station Road Car Park"}},
+		{SiteID: "S3", Name: "Hospital", T360ID: "T3"},
+	}
+
+	ids := func(sites []Site) []string {
+		var out []string
+		for _, s := range sites {
+			out = append(out, s.SiteID)
+		}
+		return out
+	}
+
+	tests := map[string][]string{
+		"station road car park":  {"S1", "S2"},
+		"STATION  ROAD CAR PARK": {"S1", "S2"},
+		"hospital":               {"S3"},
+		"unknown":                nil,
+	}
+
+	for name, want := range tests {
+		if got := ids(matchingSites(NormaliseName(name), all)); !reflect.DeepEqual(got, want) {
+			t.Errorf("matchingSites(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,395 @@
+package issuers
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileFormat - format of a registered issuer import or export file
+type FileFormat string
+
+const (
+	FormatCSV  FileFormat = "csv"
+	FormatJSON FileFormat = "json"
+)
+
+// importBatchSize - issuers written per transaction, each issuer is two writes (issuer and audit record) and
+// Firestore allows 500 writes per transaction
+const importBatchSize = 200
+
+// aliasSeparator - separates aliases within the CSV aliases column
+const aliasSeparator = "|"
+
+var csvHeader = []string{"t360_id", "issuer", "operator_name", "software_provider", "private_parking", "aliases", "deactivated", "group_id"}
+
+var ErrUnknownFileFormat = errors.New("unknown registered issuer file format")
+var ErrImportConflicts = errors.New("import has conflicts and was not applied")
+
+// ImportChange - an issuer in the import file which differs from the registry
+type ImportChange struct {
+	Issuer  RegisteredIssuer `json:"issuer"`
+	Changes []FieldChange    `json:"changes"`
+}
+
+// ImportConflict - an issuer in the import file which cannot be applied
+type ImportConflict struct {
+	Issuer RegisteredIssuer `json:"issuer"`
+	Reason string           `json:"reason"`
+}
+
+// ImportPlan - the difference between an import file and the registry
+type ImportPlan struct {
+	New       []ImportChange     `json:"new"`
+	Changed   []ImportChange     `json:"changed"`
+	Unchanged []RegisteredIssuer `json:"unchanged"`
+	Conflicts []ImportConflict   `json:"conflicts"`
+}
+
+// HasChanges returns true if applying the plan would write anything
+func (p ImportPlan) HasChanges() bool {
+	return len(p.New) > 0 || len(p.Changed) > 0
+}
+
+// Summary returns a one line description of the plan, e.g. "3 new, 1 changed, 20 unchanged, 0 conflicting"
+func (p ImportPlan) Summary() string {
+	return fmt.Sprintf("%d new, %d changed, %d unchanged, %d conflicting", len(p.New), len(p.Changed), len(p.Unchanged), len(p.Conflicts))
+}
+
+// ReadIssuers reads registered issuers from a CSV or JSON file in the format produced by WriteIssuers
+func ReadIssuers(r io.Reader, format FileFormat) ([]RegisteredIssuer, error) {
+
+	switch format {
+	case FormatJSON:
+		var issuers []RegisteredIssuer
+		if err := json.NewDecoder(r).Decode(&issuers); err != nil {
+			return nil, fmt.Errorf("reading registered issuers json: %w", err)
+		}
+		return issuers, nil
+	case FormatCSV:
+		return readIssuersCSV(r)
+	}
+
+	return nil, fmt.Errorf("%w [%s]", ErrUnknownFileFormat, format)
+}
+
+// WriteIssuers writes registered issuers ordered by t360 id so exports can be compared in version control
+func WriteIssuers(w io.Writer, format FileFormat, issuers []RegisteredIssuer) error {
+
+	sorted := make([]RegisteredIssuer, len(issuers))
+	copy(sorted, issuers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].T360ID < sorted[j].T360ID
+	})
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sorted)
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, ri := range sorted {
+			err := cw.Write([]string{
+				ri.T360ID,
+				ri.Issuer,
+				ri.OperatorName,
+				strconv.Itoa(ri.SoftwareProvider),
+				strconv.FormatBool(ri.PrivateParking),
+				strings.Join(ri.Aliases, aliasSeparator),
+				strconv.FormatBool(ri.Deactivated),
+				ri.GroupID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	return fmt.Errorf("%w [%s]", ErrUnknownFileFormat, format)
+}
+
+// ExportIssuers writes the registry in the given format
+func ExportIssuers(ctx context.Context, w io.Writer, format FileFormat, includeDeactivated bool, fs *firestore.Client) error {
+
+	issuers, err := ListIssuers(ctx, includeDeactivated, fs)
+	if err != nil {
+		return err
+	}
+
+	return WriteIssuers(w, format, issuers)
+}
+
+// PlanImport compares the issuers to import with the registry without writing anything
+func PlanImport(ctx context.Context, issuers []RegisteredIssuer, fs *firestore.Client) (ImportPlan, error) {
+
+	existing, err := allRegisteredIssuers(ctx, fs)
+	if err != nil {
+		log.Errorf("PlanImport:%v", err)
+		return ImportPlan{}, err
+	}
+
+	return planImport(issuers, existing), nil
+}
+
+// ImportIssuers plans the import and, unless dryRun is set, applies it. Nothing is written if the plan has any
+// conflicts. Writes are made in transactions of up to 200 issuers, each with an audit record, and each transaction
+// re-checks operator names against the registry as it will be after the import, the same view the plan is checked
+// against, so a concurrent change aborts the remaining batches.
+func ImportIssuers(ctx context.Context, issuers []RegisteredIssuer, actor string, dryRun bool, fs *firestore.Client) (ImportPlan, error) {
+
+	if len(strings.TrimSpace(actor)) == 0 {
+		return ImportPlan{}, ErrMissingActor
+	}
+
+	plan, err := PlanImport(ctx, issuers, fs)
+	if err != nil {
+		return plan, err
+	}
+
+	if len(plan.Conflicts) > 0 {
+		return plan, fmt.Errorf("%w: %s", ErrImportConflicts, plan.Summary())
+	}
+
+	if dryRun || !plan.HasChanges() {
+		return plan, nil
+	}
+
+	writes := append(append([]ImportChange{}, plan.New...), plan.Changed...)
+	imported := append([]RegisteredIssuer{}, plan.Unchanged...)
+	for _, ic := range writes {
+		imported = append(imported, ic.Issuer)
+	}
+
+	for start := 0; start < len(writes); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(writes) {
+			end = len(writes)
+		}
+
+		if err = applyImportBatch(ctx, writes[start:end], imported, actor, fs); err != nil {
+			log.Errorf("ImportIssuers:batch %d-%d:%v", start, end, err)
+			return plan, fmt.Errorf("import stopped after %d of %d issuers: %w", start, len(writes), err)
+		}
+	}
+
+	return plan, nil
+}
+
+// applyImportBatch writes a batch of the import, imported is every valid issuer in the import file
+func applyImportBatch(ctx context.Context, batch []ImportChange, imported []RegisteredIssuer, actor string, fs *firestore.Client) error {
+
+	return fs.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+
+		existing, err := registeredIssuersInTx(tx, fs)
+		if err != nil {
+			return err
+		}
+		after := registryAfterImport(existing, imported)
+
+		now := time.Now()
+		for _, ic := range batch {
+			ri := ic.Issuer
+			current, found := findByT360ID(ri.T360ID, existing)
+
+			if clash := operatorNameClash(ri, after); len(clash) > 0 {
+				return fmt.Errorf("%w [%s] by %s", ErrOperatorNameInUse, ri.OperatorName, clash)
+			}
+
+			action := AuditCreated
+			if found {
+				action = AuditUpdated
+				err = tx.Update(fs.Collection(REGISTERED_ISSUERS_COLLECTION).Doc(current.docID), []firestore.Update{
+					{Path: "issuer", Value: ri.Issuer},
+					{Path: "operator_name", Value: ri.OperatorName},
+					{Path: "software_provider", Value: ri.SoftwareProvider},
+					{Path: "private_parking", Value: ri.PrivateParking},
+					{Path: "aliases", Value: ri.Aliases},
+					{Path: "deactivated", Value: ri.Deactivated},
+					{Path: "group_id", Value: ri.GroupID},
+				})
+			} else {
+				err = tx.Create(fs.Collection(REGISTERED_ISSUERS_COLLECTION).NewDoc(), ri)
+			}
+			if err != nil {
+				return err
+			}
+
+			err = tx.Create(fs.Collection(REGISTERED_ISSUERS_AUDIT_COLLECTION).NewDoc(), AuditRecord{
+				T360ID:    ri.T360ID,
+				Action:    action,
+				Actor:     actor,
+				Reason:    "bulk import",
+				Changes:   ic.Changes,
+				ChangedAt: now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func planImport(issuers []RegisteredIssuer, existing []RegisteredIssuer) ImportPlan {
+
+	plan := ImportPlan{}
+
+	seen := map[string]bool{}
+	var valid []RegisteredIssuer
+	for _, ri := range issuers {
+		if err := ri.Validate(); err != nil {
+			plan.Conflicts = append(plan.Conflicts, ImportConflict{Issuer: ri, Reason: err.Error()})
+			continue
+		}
+		if seen[ri.T360ID] {
+			plan.Conflicts = append(plan.Conflicts, ImportConflict{Issuer: ri, Reason: "t360_id appears more than once in the import"})
+			continue
+		}
+		seen[ri.T360ID] = true
+		valid = append(valid, ri)
+	}
+
+	after := registryAfterImport(existing, valid)
+
+	for _, ri := range valid {
+
+		if clash := operatorNameClash(ri, after); len(clash) > 0 {
+			plan.Conflicts = append(plan.Conflicts, ImportConflict{
+				Issuer: ri,
+				Reason: fmt.Sprintf("operator name %s is also used by %s", ri.OperatorName, clash),
+			})
+			continue
+		}
+
+		current, found := findByT360ID(ri.T360ID, existing)
+		if !found {
+			plan.New = append(plan.New, ImportChange{Issuer: ri, Changes: diffIssuers(RegisteredIssuer{}, ri)})
+			continue
+		}
+
+		if changes := diffIssuers(current, ri); len(changes) > 0 {
+			plan.Changed = append(plan.Changed, ImportChange{Issuer: ri, Changes: changes})
+		} else {
+			plan.Unchanged = append(plan.Unchanged, ri)
+		}
+	}
+
+	return plan
+}
+
+// registryAfterImport returns the registry keyed by t360 id as it will be once the imported issuers are written, used
+// to find operator name clashes both with the registry and within the import file
+func registryAfterImport(existing []RegisteredIssuer, imported []RegisteredIssuer) map[string]RegisteredIssuer {
+
+	after := make(map[string]RegisteredIssuer, len(existing)+len(imported))
+	for _, e := range existing {
+		after[e.T360ID] = e
+	}
+	for _, ri := range imported {
+		after[ri.T360ID] = ri
+	}
+
+	return after
+}
+
+func operatorNameClash(ri RegisteredIssuer, registry map[string]RegisteredIssuer) string {
+
+	if ri.Deactivated {
+		return ""
+	}
+
+	name := NormaliseName(ri.OperatorName)
+	for id, other := range registry {
+		if id != ri.T360ID && !other.Deactivated && NormaliseName(other.OperatorName) == name {
+			return id
+		}
+	}
+
+	return ""
+}
+
+func readIssuersCSV(r io.Reader) ([]RegisteredIssuer, error) {
+
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading registered issuers csv header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	// every column is required, a missing column would otherwise import as empty values over the registry
+	for _, required := range csvHeader {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("registered issuers csv is missing the %s column", required)
+		}
+	}
+
+	var issuers []RegisteredIssuer
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("reading registered issuers csv line %d: %w", line, err)
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		ri := RegisteredIssuer{
+			T360ID:       value("t360_id"),
+			Issuer:       value("issuer"),
+			OperatorName: value("operator_name"),
+			GroupID:      value("group_id"),
+		}
+
+		if v := value("software_provider"); len(v) > 0 {
+			if ri.SoftwareProvider, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("registered issuers csv line %d software_provider: %w", line, err)
+			}
+		}
+		if v := value("private_parking"); len(v) > 0 {
+			if ri.PrivateParking, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("registered issuers csv line %d private_parking: %w", line, err)
+			}
+		}
+		if v := value("deactivated"); len(v) > 0 {
+			if ri.Deactivated, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("registered issuers csv line %d deactivated: %w", line, err)
+			}
+		}
+		if v := value("aliases"); len(v) > 0 {
+			ri.Aliases = strings.Split(v, aliasSeparator)
+		}
+
+		issuers = append(issuers, ri)
+	}
+
+	return issuers, nil
+}
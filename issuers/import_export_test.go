@@ -0,0 +1,105 @@
+package issuers
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadIssuersCSV(t *testing.T) {
+
+	issuers := []RegisteredIssuer{
+		{T360ID: "T2", Issuer: "Beta Parking", OperatorName: "BETA PARKING LTD", SoftwareProvider: 3, Aliases: []string{"BETA", "BETA PARK"}, GroupID: "G1"},
+		{T360ID: "T1", Issuer: "Alpha Parking", OperatorName: "ALPHA PARKING LTD", PrivateParking: true, Deactivated: true},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteIssuers(&buf, FormatCSV, issuers); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadIssuers(&buf, FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []RegisteredIssuer{issuers[1], issuers[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// a file without every column would clear the missing fields in the registry
+	_, err = ReadIssuers(strings.NewReader("t360_id,issuer,operator_name\nT1,Alpha,ALPHA\n"), FormatCSV)
+	if err == nil || !strings.Contains(err.Error(), "software_provider") {
+		t.Errorf("expected a missing column error, got %v", err)
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+
+	existing := []RegisteredIssuer{
+		{T360ID: "T1", Issuer: "Alpha", OperatorName: "ALPHA PARKING"},
+		{T360ID: "T2", Issuer: "Beta", OperatorName: "BETA PARKING"},
+		{T360ID: "T3", Issuer: "Gamma", OperatorName: "GAMMA PARKING", Deactivated: true},
+	}
+
+	plan := planImport([]RegisteredIssuer{
+		{T360ID: "T1", Issuer: "Alpha", OperatorName: "ALPHA PARKING"},
+		// takes Beta's name, which is renamed by the same import
+		{T360ID: "T4", Issuer: "Delta", OperatorName: "BETA PARKING"},
+		{T360ID: "T2", Issuer: "Beta", OperatorName: "BETA CAR PARKS"},
+		// clashes with T1, which is reported as well
+		{T360ID: "T5", Issuer: "Epsilon", OperatorName: "Alpha Parking"},
+		// deactivated names can be reused
+		{T360ID: "T6", Issuer: "Zeta", OperatorName: "GAMMA PARKING"},
+		{T360ID: "T6", Issuer: "Zeta", OperatorName: "ZETA PARKING"},
+		{T360ID: "", Issuer: "No id", OperatorName: "NO ID"},
+	}, existing)
+
+	ids := func(changes []ImportChange) []string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, c.Issuer.T360ID)
+		}
+		return out
+	}
+
+	if got := ids(plan.New); !reflect.DeepEqual(got, []string{"T4", "T6"}) {
+		t.Errorf("new %v", got)
+	}
+	if got := ids(plan.Changed); !reflect.DeepEqual(got, []string{"T2"}) {
+		t.Errorf("changed %v", got)
+	}
+	if len(plan.Unchanged) != 0 {
+		t.Errorf("unchanged %v", plan.Unchanged)
+	}
+	if len(plan.Conflicts) != 4 {
+		t.Errorf("conflicts %+v", plan.Conflicts)
+	}
+}
+
+func TestRegistryAfterImport(t *testing.T) {
+
+	existing := []RegisteredIssuer{
+		{T360ID: "T1", OperatorName: "ALPHA PARKING"},
+		{T360ID: "T2", OperatorName: "BETA PARKING"},
+	}
+	imported := []RegisteredIssuer{
+		{T360ID: "T2", OperatorName: "BETA CAR PARKS"},
+		{T360ID: "T3", OperatorName: "BETA PARKING"},
+	}
+
+	after := registryAfterImport(existing, imported)
+	if len(after) != 3 || after["T2"].OperatorName != "BETA CAR PARKS" {
+		t.Errorf("unexpected registry %+v", after)
+	}
+
+	// checked against the registry before the import T3 would clash with T2
+	if clash := operatorNameClash(imported[1], after); len(clash) > 0 {
+		t.Errorf("unexpected clash with %s", clash)
+	}
+	if clash := operatorNameClash(RegisteredIssuer{T360ID: "T4", OperatorName: "alpha  parking"}, after); clash != "T1" {
+		t.Errorf("got clash %q, want T1", clash)
+	}
+}
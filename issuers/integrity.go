@@ -0,0 +1,221 @@
+package issuers
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/search"
+	"github.com/transfer360/go-transfer360/software_provider"
+	"google.golang.org/api/iterator"
+	"sort"
+)
+
+// SearchIssue - a search record with a problem found by CheckIntegrity
+type SearchIssue struct {
+	DocumentID string `json:"document_id"`
+	Sref       string `json:"sref"`
+	IssuerID   string `json:"issuer_id,omitempty"`
+}
+
+// IntegrityReport - problems found in the registered_issuers and searches collections
+type IntegrityReport struct {
+	IssuersChecked  int `json:"issuers_checked"`
+	SearchesChecked int `json:"searches_checked"`
+	// DuplicateT360IDs - t360 id to the ids of the registered_issuers documents sharing it
+	DuplicateT360IDs map[string][]string `json:"duplicate_t360_ids,omitempty"`
+	// DuplicateOperatorNames - normalised operator name to the t360 ids of the active issuers sharing it
+	DuplicateOperatorNames map[string][]string `json:"duplicate_operator_names,omitempty"`
+	// MissingSoftwareProvider - t360 ids of issuers without a software provider
+	MissingSoftwareProvider []string `json:"missing_software_provider,omitempty"`
+	// UnknownSoftwareProvider - t360 ids of issuers whose software provider is not in the software provider registry,
+	// only checked once the registry has been populated
+	UnknownSoftwareProvider []string `json:"unknown_software_provider,omitempty"`
+	// UnknownIssuerSearches - searches whose issuer id is not in registered_issuers
+	UnknownIssuerSearches []SearchIssue `json:"unknown_issuer_searches,omitempty"`
+	// LegacyIssuerIDSearches - searches with the issuer only in the legacy client.issuerid field
+	LegacyIssuerIDSearches []SearchIssue `json:"legacy_issuer_id_searches,omitempty"`
+	// Fixed - searches updated when the check was run in fix mode
+	Fixed int `json:"fixed"`
+	// FixFailed - legacy searches which could not be updated in fix mode, with the error in Reason
+	FixFailed []FixFailure `json:"fix_failed,omitempty"`
+}
+
+// FixFailure - a search CheckIntegrity could not update
+type FixFailure struct {
+	SearchIssue
+	Reason string `json:"reason"`
+}
+
+// HasProblems returns true if the report found anything
+func (r IntegrityReport) HasProblems() bool {
+	return len(r.DuplicateT360IDs) > 0 || len(r.DuplicateOperatorNames) > 0 || len(r.MissingSoftwareProvider) > 0 ||
+		len(r.UnknownSoftwareProvider) > 0 ||
+		len(r.UnknownIssuerSearches) > 0 || len(r.LegacyIssuerIDSearches) > 0 || len(r.FixFailed) > 0
+}
+
+// CheckIntegrity scans registered_issuers and searches for duplicate issuers, issuers without a software provider,
+// searches referencing unknown issuers and searches using the legacy client.issuerid field. With fix set, legacy
+// searches have client.issuer_id filled in from client.issuerid, nothing else is changed as the other problems need
+// a person to decide which record is correct. Fixed counts the updates Firestore confirmed, failed updates are
+// listed in FixFailed.
+func CheckIntegrity(ctx context.Context, fix bool, fs *firestore.Client) (IntegrityReport, error) {
+
+	report := newIntegrityReport()
+
+	issuers, err := allRegisteredIssuers(ctx, fs)
+	if err != nil {
+		log.Errorf("CheckIntegrity:%v", err)
+		return report, err
+	}
+
+	providers, err := software_provider.NewFirestoreStore(fs).List(ctx)
+	if err != nil {
+		log.Errorf("CheckIntegrity:%v", err)
+		return report, err
+	}
+
+	known := report.checkIssuers(issuers, providers)
+
+	type fixJob struct {
+		issue SearchIssue
+		job   *firestore.BulkWriterJob
+	}
+	var jobs []fixJob
+
+	var bw *firestore.BulkWriter
+	if fix {
+		bw = fs.BulkWriter(ctx)
+	}
+
+	itr := fs.Collection(search.SEARCHES_COLLECTION).Select("sref", "client.issuerid", "client.issuer_id").Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			log.Errorf("CheckIntegrity:searches:%v", err)
+			return report, err
+		}
+
+		record := integritySearch{}
+		if err = doc.DataTo(&record); err != nil {
+			log.Errorf("CheckIntegrity:searches:[%s]:%v", doc.Ref.ID, err)
+			return report, err
+		}
+
+		issue, legacy := report.checkSearch(doc.Ref.ID, record, known)
+		if legacy && fix {
+			job, err := bw.Update(doc.Ref, []firestore.Update{{Path: "client.issuer_id", Value: issue.IssuerID}})
+			if err != nil {
+				log.Errorf("CheckIntegrity:fix:[%s]:%v", doc.Ref.ID, err)
+				bw.End()
+				return report, fmt.Errorf("fixing search %s: %w", doc.Ref.ID, err)
+			}
+			jobs = append(jobs, fixJob{issue: issue, job: job})
+		}
+	}
+
+	if fix {
+		// End waits for every queued write, a job's result is only known once it has been sent
+		bw.End()
+		for _, j := range jobs {
+			_, err := j.job.Results()
+			report.recordFix(j.issue, err)
+		}
+	}
+
+	return report, nil
+}
+
+// integritySearch - the fields of a search document CheckIntegrity reads
+type integritySearch struct {
+	Sref   string `firestore:"sref"`
+	Client struct {
+		IssuerID  string `firestore:"issuerid"`
+		Issuer_ID string `firestore:"issuer_id"`
+	} `firestore:"client"`
+}
+
+func newIntegrityReport() IntegrityReport {
+	return IntegrityReport{
+		DuplicateT360IDs:       map[string][]string{},
+		DuplicateOperatorNames: map[string][]string{},
+	}
+}
+
+// checkIssuers adds the registry problems to the report and returns the set of known t360 ids. Unknown software
+// providers are only reported once the provider registry has been populated.
+func (r *IntegrityReport) checkIssuers(issuers []RegisteredIssuer, providers []software_provider.SoftwareProvider) map[string]bool {
+
+	r.IssuersChecked = len(issuers)
+
+	knownProviders := map[int]bool{}
+	for _, p := range providers {
+		knownProviders[p.ID] = true
+	}
+
+	known := map[string]bool{}
+	byT360ID := map[string][]string{}
+	byName := map[string][]string{}
+	for _, ri := range issuers {
+		known[ri.T360ID] = true
+		byT360ID[ri.T360ID] = append(byT360ID[ri.T360ID], ri.docID)
+		if !ri.Deactivated && len(ri.OperatorName) > 0 {
+			name := NormaliseName(ri.OperatorName)
+			byName[name] = append(byName[name], ri.T360ID)
+		}
+		if ri.SoftwareProvider == 0 {
+			r.MissingSoftwareProvider = append(r.MissingSoftwareProvider, ri.T360ID)
+		} else if len(knownProviders) > 0 && !knownProviders[ri.SoftwareProvider] {
+			r.UnknownSoftwareProvider = append(r.UnknownSoftwareProvider, ri.T360ID)
+		}
+	}
+	for id, docs := range byT360ID {
+		if len(docs) > 1 {
+			r.DuplicateT360IDs[id] = docs
+		}
+	}
+	for name, ids := range byName {
+		if len(ids) > 1 {
+			r.DuplicateOperatorNames[name] = ids
+		}
+	}
+	sort.Strings(r.MissingSoftwareProvider)
+	sort.Strings(r.UnknownSoftwareProvider)
+
+	return known
+}
+
+// checkSearch adds any problems with a search to the report, returning the issue and true if the search only has the
+// legacy client.issuerid and so can be fixed
+func (r *IntegrityReport) checkSearch(docID string, record integritySearch, known map[string]bool) (SearchIssue, bool) {
+
+	r.SearchesChecked++
+
+	issue := SearchIssue{DocumentID: docID, Sref: record.Sref, IssuerID: record.Client.Issuer_ID}
+	legacy := len(issue.IssuerID) == 0 && len(record.Client.IssuerID) > 0
+	if legacy {
+		issue.IssuerID = record.Client.IssuerID
+		r.LegacyIssuerIDSearches = append(r.LegacyIssuerIDSearches, issue)
+	}
+
+	if len(issue.IssuerID) > 0 && !known[issue.IssuerID] {
+		r.UnknownIssuerSearches = append(r.UnknownIssuerSearches, issue)
+	}
+
+	return issue, legacy
+}
+
+// recordFix counts a confirmed fix or lists the failure with its reason
+func (r *IntegrityReport) recordFix(issue SearchIssue, err error) {
+
+	if err != nil {
+		log.Errorf("CheckIntegrity:fix:[%s]:%v", issue.DocumentID, err)
+		r.FixFailed = append(r.FixFailed, FixFailure{SearchIssue: issue, Reason: err.Error()})
+		return
+	}
+	r.Fixed++
+}
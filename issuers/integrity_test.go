@@ -0,0 +1,119 @@
+package issuers
+
+import (
+	"errors"
+	"github.com/transfer360/go-transfer360/software_provider"
+	"reflect"
+	"testing"
+)
+
+func TestCheckIssuers(t *testing.T) {
+
+	issuers := []RegisteredIssuer{
+		{T360ID: "T1", OperatorName: "Smart Parking Ltd", SoftwareProvider: 1, docID: "d1"},
+		{T360ID: "T1", OperatorName: "Other Name", SoftwareProvider: 1, docID: "d2"},
+		{T360ID: "T2", OperatorName: "SMART PARKING LIMITED", SoftwareProvider: 9, docID: "d3"},
+		{T360ID: "T3", OperatorName: "Smart Parking", SoftwareProvider: 1, Deactivated: true, docID: "d4"},
+		{T360ID: "T4", OperatorName: "Delta", docID: "d5"},
+	}
+
+	report := newIntegrityReport()
+	known := report.checkIssuers(issuers, []software_provider.SoftwareProvider{{ID: 1}, {ID: 2}})
+
+	if report.IssuersChecked != 5 {
+		t.Errorf("checked %d issuers, want 5", report.IssuersChecked)
+	}
+	if want := map[string][]string{"T1": {"d1", "d2"}}; !reflect.DeepEqual(report.DuplicateT360IDs, want) {
+		t.Errorf("duplicate t360 ids %v, want %v", report.DuplicateT360IDs, want)
+	}
+	// the deactivated issuer's name is not a clash
+	if want := map[string][]string{"SMART PARKING": {"T1", "T2"}}; !reflect.DeepEqual(report.DuplicateOperatorNames, want) {
+		t.Errorf("duplicate operator names %v, want %v", report.DuplicateOperatorNames, want)
+	}
+	if want := []string{"T4"}; !reflect.DeepEqual(report.MissingSoftwareProvider, want) {
+		t.Errorf("missing software provider %v, want %v", report.MissingSoftwareProvider, want)
+	}
+	if want := []string{"T2"}; !reflect.DeepEqual(report.UnknownSoftwareProvider, want) {
+		t.Errorf("unknown software provider %v, want %v", report.UnknownSoftwareProvider, want)
+	}
+	if want := map[string]bool{"T1": true, "T2": true, "T3": true, "T4": true}; !reflect.DeepEqual(known, want) {
+		t.Errorf("known %v, want %v", known, want)
+	}
+	if !report.HasProblems() {
+		t.Error("expected the report to have problems")
+	}
+
+	// an empty provider registry can't say a provider is unknown
+	report = newIntegrityReport()
+	report.checkIssuers(issuers[2:3], nil)
+	if len(report.UnknownSoftwareProvider) != 0 || report.HasProblems() {
+		t.Errorf("expected no problems without a provider registry, got %+v", report)
+	}
+}
+
+func integrityRecord(sref, legacyID, issuerID string) integritySearch {
+	r := integritySearch{Sref: sref}
+	r.Client.IssuerID = legacyID
+	r.Client.Issuer_ID = issuerID
+	return r
+}
+
+func TestCheckSearch(t *testing.T) {
+
+	known := map[string]bool{"T1": true}
+
+	tests := []struct {
+		name        string
+		record      integritySearch
+		wantIssue   SearchIssue
+		wantLegacy  bool
+		wantUnknown bool
+	}{
+		{"current field", integrityRecord("S1", "", "T1"), SearchIssue{DocumentID: "doc", Sref: "S1", IssuerID: "T1"}, false, false},
+		{"both fields use the current one", integrityRecord("S2", "T9", "T1"), SearchIssue{DocumentID: "doc", Sref: "S2", IssuerID: "T1"}, false, false},
+		{"legacy field only", integrityRecord("S3", "T1", ""), SearchIssue{DocumentID: "doc", Sref: "S3", IssuerID: "T1"}, true, false},
+		{"legacy field with an unknown issuer", integrityRecord("S4", "T9", ""), SearchIssue{DocumentID: "doc", Sref: "S4", IssuerID: "T9"}, true, true},
+		{"unknown issuer", integrityRecord("S5", "", "T9"), SearchIssue{DocumentID: "doc", Sref: "S5", IssuerID: "T9"}, false, true},
+		{"no issuer", integrityRecord("S6", "", ""), SearchIssue{DocumentID: "doc", Sref: "S6"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			report := newIntegrityReport()
+			issue, legacy := report.checkSearch("doc", tt.record, known)
+
+			if issue != tt.wantIssue || legacy != tt.wantLegacy {
+				t.Errorf("got %+v %v, want %+v %v", issue, legacy, tt.wantIssue, tt.wantLegacy)
+			}
+			if report.SearchesChecked != 1 {
+				t.Errorf("checked %d searches, want 1", report.SearchesChecked)
+			}
+			if got := len(report.LegacyIssuerIDSearches) == 1; got != tt.wantLegacy {
+				t.Errorf("legacy searches %+v", report.LegacyIssuerIDSearches)
+			}
+			if got := len(report.UnknownIssuerSearches) == 1; got != tt.wantUnknown {
+				t.Errorf("unknown issuer searches %+v", report.UnknownIssuerSearches)
+			}
+		})
+	}
+}
+
+func TestRecordFix(t *testing.T) {
+
+	report := newIntegrityReport()
+	report.recordFix(SearchIssue{DocumentID: "d1"}, nil)
+	report.recordFix(SearchIssue{DocumentID: "d2"}, errors.New("precondition failed"))
+	report.recordFix(SearchIssue{DocumentID: "d3"}, nil)
+
+	if report.Fixed != 2 {
+		t.Errorf("fixed %d, want 2", report.Fixed)
+	}
+	want := []FixFailure{{SearchIssue: SearchIssue{DocumentID: "d2"}, Reason: "precondition failed"}}
+	if !reflect.DeepEqual(report.FixFailed, want) {
+		t.Errorf("fix failed %+v, want %+v", report.FixFailed, want)
+	}
+	if !report.HasProblems() {
+		t.Error("a failed fix is a problem")
+	}
+}
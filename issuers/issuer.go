@@ -28,7 +28,7 @@ func GetIssuerInformationFromT360ID(ctx context.Context, issuerID string, fsclie
 		PrivateParking   bool   `firestore:"private_parking"`
 	}{}
 
-	itr := fsclient.Collection("registered_issuers").Where("t360_id", "==", issuerID).Documents(ctx)
+	itr := fsclient.Collection(REGISTERED_ISSUERS_COLLECTION).Where("t360_id", "==", issuerID).Documents(ctx)
 	for {
 		doc, err := itr.Next()
 		if err != nil {
@@ -0,0 +1,230 @@
+package issuers
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultFuzzyMinScore - lowest score returned by FuzzyFromOperatorsName when no minimum is given
+const DefaultFuzzyMinScore = 0.6
+
+// Candidate - an issuer returned from a fuzzy match with a score between 0 and 1, where 1 is an exact match on the
+// normalised name
+type Candidate struct {
+	IssuerInformation
+	MatchedName string  `json:"matched_name"`
+	Score       float64 `json:"score"`
+}
+
+var legalSuffixes = []string{"LIMITED", "LTD", "PLC", "LLP", "LP", "CO", "COMPANY"}
+
+// NormaliseName reduces an operator or issuer name to a form used for comparison, upper case with punctuation removed,
+// "&" read as "AND", a leading "THE" dropped and trailing company suffixes such as Ltd, Limited and PLC removed
+func NormaliseName(name string) string {
+
+	name = strings.ReplaceAll(strings.ToUpper(name), "&", " AND ")
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		if r == '\'' || r == '.' {
+			return -1
+		}
+		return ' '
+	}, name)
+
+	words := strings.Fields(name)
+	if len(words) > 1 && words[0] == "THE" {
+		words = words[1:]
+	}
+
+	for len(words) > 1 && isLegalSuffix(words[len(words)-1]) {
+		words = words[:len(words)-1]
+	}
+
+	return strings.Join(words, " ")
+}
+
+func isLegalSuffix(word string) bool {
+	for _, s := range legalSuffixes {
+		if word == s {
+			return true
+		}
+	}
+	return false
+}
+
+// FromNormalisedName finds the issuer whose operator name, issuer name or one of its aliases matches the given name
+// once both have been normalised with NormaliseName
+func FromNormalisedName(ctx context.Context, operatorName string, fs *firestore.Client) (IssuerInformation, error) {
+
+	want := NormaliseName(operatorName)
+	if len(want) == 0 {
+		return IssuerInformation{}, fmt.Errorf("%w with name %s", ErrIssuerNotFound, operatorName)
+	}
+
+	issuers, err := allRegisteredIssuers(ctx, fs)
+	if err != nil {
+		log.Errorf("FromNormalisedName:[%s]:%v", operatorName, err)
+		return IssuerInformation{}, err
+	}
+
+	for _, ri := range issuers {
+		if ri.Deactivated {
+			continue
+		}
+		for _, n := range ri.names() {
+			if NormaliseName(n) == want {
+				return ri.information(), nil
+			}
+		}
+	}
+
+	return IssuerInformation{}, fmt.Errorf("%w with name %s", ErrIssuerNotFound, operatorName)
+}
+
+// FuzzyFromOperatorsName scores every registered issuer against the given name and returns those scoring at least
+// minScore, best match first. A minScore of 0 uses DefaultFuzzyMinScore. The result is a ranked list for a person or
+// caller to choose from rather than a single guess.
+func FuzzyFromOperatorsName(ctx context.Context, operatorName string, minScore float64, fs *firestore.Client) ([]Candidate, error) {
+
+	if minScore <= 0 {
+		minScore = DefaultFuzzyMinScore
+	}
+
+	want := NormaliseName(operatorName)
+	if len(want) == 0 {
+		return nil, nil
+	}
+
+	issuers, err := allRegisteredIssuers(ctx, fs)
+	if err != nil {
+		log.Errorf("FuzzyFromOperatorsName:[%s]:%v", operatorName, err)
+		return nil, err
+	}
+
+	return rankCandidates(want, issuers, minScore), nil
+}
+
+// rankCandidates scores the active issuers against a normalised name on their best matching name and returns those
+// scoring at least minScore, best match first
+func rankCandidates(want string, issuers []RegisteredIssuer, minScore float64) []Candidate {
+
+	var candidates []Candidate
+	for _, ri := range issuers {
+		if ri.Deactivated {
+			continue
+		}
+		best := Candidate{IssuerInformation: ri.information()}
+		for _, n := range ri.names() {
+			if len(n) == 0 {
+				continue
+			}
+			if score := nameSimilarity(want, NormaliseName(n)); score > best.Score {
+				best.Score = score
+				best.MatchedName = n
+			}
+		}
+		if best.Score >= minScore {
+			candidates = append(candidates, best)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates
+}
+
+func allRegisteredIssuers(ctx context.Context, fs *firestore.Client) ([]RegisteredIssuer, error) {
+
+	var issuers []RegisteredIssuer
+
+	itr := fs.Collection(REGISTERED_ISSUERS_COLLECTION).Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			return nil, err
+		}
+
+		ri := RegisteredIssuer{}
+		if err = doc.DataTo(&ri); err != nil {
+			return nil, err
+		}
+		ri.docID = doc.Ref.ID
+		issuers = append(issuers, ri)
+	}
+
+	return issuers, nil
+}
+
+// nameSimilarity returns the better of the edit distance similarity and the share of words in common, so both
+// misspellings and missing or reordered words score well
+func nameSimilarity(a, b string) float64 {
+
+	if a == b {
+		return 1
+	}
+
+	longest := len([]rune(a))
+	if l := len([]rune(b)); l > longest {
+		longest = l
+	}
+	if longest == 0 {
+		return 0
+	}
+
+	edit := 1 - float64(levenshtein(a, b))/float64(longest)
+
+	aw, bw := strings.Fields(a), strings.Fields(b)
+	common := 0
+	for _, w := range aw {
+		for _, x := range bw {
+			if w == x {
+				common++
+				break
+			}
+		}
+	}
+	words := 2 * float64(common) / float64(len(aw)+len(bw))
+
+	if words > edit {
+		return words
+	}
+	return edit
+}
+
+func levenshtein(a, b string) int {
+
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(br)]
+}
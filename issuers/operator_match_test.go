@@ -0,0 +1,133 @@
+package issuers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormaliseName(t *testing.T) {
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Smart Parking Ltd", "SMART PARKING"},
+		{"SMART PARKING LIMITED", "SMART PARKING"},
+		{"smart parking", "SMART PARKING"},
+		{"The A&B Car Parks Co. Ltd", "A AND B CAR PARKS"},
+		{"O'Brien's Parking", "OBRIENS PARKING"},
+		{"P.C.M. Ltd", "PCM"},
+		{"Euro-Car-Parks (UK) plc", "EURO CAR PARKS UK"},
+		{"  Parking   Eye  LLP ", "PARKING EYE"},
+		{"Limited", "LIMITED"},
+		{"The", "THE"},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormaliseName(tt.name); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"ABC", "", 3},
+		{"", "ABC", 3},
+		{"KITTEN", "SITTING", 3},
+		{"FLAW", "LAWN", 2},
+		{"CAFÉ", "CAFE", 1},
+		{"SMART PARKING", "SMART PARKING", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+			if got := levenshtein(tt.b, tt.a); got != tt.want {
+				t.Errorf("reversed got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"SMART PARKING", "SMART PARKING", 1},
+		{"SMART PARKING", "PARKING SMART", 1},
+		{"ALPHA PARKING", "ALPHA PARKNG", 1 - 1.0/13},
+		{"SMART PARKING", "SMART CAR PARKING", 0.8},
+		{"SMART PARKING", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := nameSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankCandidates(t *testing.T) {
+
+	issuers := []RegisteredIssuer{
+		{T360ID: "T2", Issuer: "Smart Car Parking", OperatorName: "SMART CAR PARKING"},
+		{T360ID: "T4", Issuer: "Zebra", OperatorName: "ZEBRA"},
+		{T360ID: "T3", Issuer: "Smart Parking", OperatorName: "SMART PARKING", Deactivated: true},
+		{T360ID: "T5", Issuer: "Other", OperatorName: "OTHER CO", Aliases: []string{"SMART PARKNG"}},
+		{T360ID: "T1", Issuer: "Smart Parking", OperatorName: "Smart Parking Limited"},
+	}
+
+	type want struct {
+		t360ID  string
+		matched string
+		score   float64
+	}
+
+	tests := []struct {
+		name     string
+		minScore float64
+		want     []want
+	}{
+		{"default minimum", DefaultFuzzyMinScore, []want{
+			{"T1", "Smart Parking Limited", 1},
+			{"T5", "SMART PARKNG", 1 - 1.0/13},
+			{"T2", "SMART CAR PARKING", 0.8},
+		}},
+		{"higher minimum", 0.85, []want{
+			{"T1", "Smart Parking Limited", 1},
+			{"T5", "SMART PARKNG", 1 - 1.0/13},
+		}},
+		{"exact only", 1, []want{
+			{"T1", "Smart Parking Limited", 1},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rankCandidates(NormaliseName("Smart Parking Ltd"), issuers, tt.minScore)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d candidates %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if got[i].T360ID != w.t360ID || got[i].MatchedName != w.matched || math.Abs(got[i].Score-w.score) > 1e-9 {
+					t.Errorf("candidate %d got %s %q %v, want %s %q %v", i, got[i].T360ID, got[i].MatchedName, got[i].Score, w.t360ID, w.matched, w.score)
+				}
+			}
+		})
+	}
+}
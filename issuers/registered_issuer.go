@@ -0,0 +1,484 @@
+package issuers
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const REGISTERED_ISSUERS_COLLECTION = "registered_issuers"
+const REGISTERED_ISSUERS_AUDIT_COLLECTION = "registered_issuers_audit"
+
+var ErrIssuerAlreadyExists = errors.New("issuer with t360 id already exists")
+var ErrOperatorNameInUse = errors.New("operator name is already used by another issuer")
+var ErrMissingActor = errors.New("actor is required to change registered issuers")
+
+// RegisteredIssuer - a document in the registered_issuers collection
+type RegisteredIssuer struct {
+	T360ID           string   `json:"t360_id" firestore:"t360_id" validate:"required"`
+	Issuer           string   `json:"issuer" firestore:"issuer" validate:"required"`
+	OperatorName     string   `json:"operator_name" firestore:"operator_name" validate:"required"`
+	SoftwareProvider int      `json:"software_provider" firestore:"software_provider" validate:"gte=0"`
+	PrivateParking   bool     `json:"private_parking" firestore:"private_parking"`
+	Aliases          []string `json:"aliases,omitempty" firestore:"aliases,omitempty"`
+	// GroupID - the Group this operator belongs to, if any
+	GroupID string `json:"group_id,omitempty" firestore:"group_id,omitempty"`
+	// Deactivated issuers are skipped by name lookups but can still be read by t360 id for historic searches
+	Deactivated bool `json:"deactivated,omitempty" firestore:"deactivated"`
+
+	docID string
+}
+
+// AuditAction - the kind of change made to a registered issuer
+type AuditAction string
+
+const (
+	AuditCreated     AuditAction = "created"
+	AuditUpdated     AuditAction = "updated"
+	AuditDeactivated AuditAction = "deactivated"
+)
+
+// FieldChange - a single field changed on a registered issuer
+type FieldChange struct {
+	Field string      `json:"field" firestore:"field"`
+	From  interface{} `json:"from" firestore:"from"`
+	To    interface{} `json:"to" firestore:"to"`
+}
+
+// AuditRecord - a document in the registered_issuers_audit collection recording who changed what
+type AuditRecord struct {
+	T360ID    string        `json:"t360_id" firestore:"t360_id"`
+	Action    AuditAction   `json:"action" firestore:"action"`
+	Actor     string        `json:"actor" firestore:"actor"`
+	Reason    string        `json:"reason,omitempty" firestore:"reason,omitempty"`
+	Changes   []FieldChange `json:"changes" firestore:"changes"`
+	ChangedAt time.Time     `json:"changed_at" firestore:"changed_at"`
+}
+
+func (r RegisteredIssuer) information() IssuerInformation {
+	return IssuerInformation{
+		T360ID:         r.T360ID,
+		ClientID:       r.T360ID,
+		IssuerID:       r.T360ID,
+		SoftwareID:     r.SoftwareProvider,
+		Issuer:         r.Issuer,
+		PrivateParking: r.PrivateParking,
+	}
+}
+
+// names returns every name the issuer can be known by, the operator name, issuer name and configured aliases
+func (r RegisteredIssuer) names() []string {
+	n := []string{r.OperatorName, r.Issuer}
+	return append(n, r.Aliases...)
+}
+
+// Validate checks the required fields are present and tidies whitespace
+func (r *RegisteredIssuer) Validate() error {
+
+	r.T360ID = strings.TrimSpace(r.T360ID)
+	r.Issuer = strings.TrimSpace(r.Issuer)
+	r.OperatorName = strings.TrimSpace(r.OperatorName)
+	r.GroupID = strings.TrimSpace(r.GroupID)
+
+	var aliases []string
+	for _, a := range r.Aliases {
+		if a = strings.TrimSpace(a); len(a) > 0 {
+			aliases = append(aliases, a)
+		}
+	}
+	r.Aliases = aliases
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// CreateIssuer adds a new registered issuer, the t360 id must not already exist and the operator name must not be used
+// by another active issuer
+func CreateIssuer(ctx context.Context, ri RegisteredIssuer, actor string, fs *firestore.Client) error {
+
+	if len(strings.TrimSpace(actor)) == 0 {
+		return ErrMissingActor
+	}
+
+	if err := ri.Validate(); err != nil {
+		return err
+	}
+	ri.Deactivated = false
+
+	err := fs.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+
+		existing, err := registeredIssuersInTx(tx, fs)
+		if err != nil {
+			return err
+		}
+
+		changes, err := planCreate(ri, existing)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Create(fs.Collection(REGISTERED_ISSUERS_COLLECTION).NewDoc(), ri); err != nil {
+			return err
+		}
+
+		return tx.Create(fs.Collection(REGISTERED_ISSUERS_AUDIT_COLLECTION).NewDoc(), AuditRecord{
+			T360ID:    ri.T360ID,
+			Action:    AuditCreated,
+			Actor:     actor,
+			Changes:   changes,
+			ChangedAt: time.Now(),
+		})
+	})
+
+	if err != nil && !errors.Is(err, ErrIssuerAlreadyExists) && !errors.Is(err, ErrOperatorNameInUse) {
+		log.Errorf("CreateIssuer:[%s]:%v", ri.T360ID, err)
+	}
+
+	return err
+}
+
+// UpdateIssuer replaces the managed fields of the registered issuer with the same t360 id, fields not managed by this
+// package are left untouched. An empty GroupID keeps the issuer's current group, use SetIssuerGroup to move or remove
+// it. Nothing is written if the issuer is unchanged.
+func UpdateIssuer(ctx context.Context, ri RegisteredIssuer, actor string, fs *firestore.Client) error {
+
+	if len(strings.TrimSpace(actor)) == 0 {
+		return ErrMissingActor
+	}
+
+	if err := ri.Validate(); err != nil {
+		return err
+	}
+
+	err := fs.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+
+		existing, err := registeredIssuersInTx(tx, fs)
+		if err != nil {
+			return err
+		}
+
+		current, updated, changes, err := planUpdate(ri, existing)
+		if err != nil || len(changes) == 0 {
+			return err
+		}
+
+		err = tx.Update(fs.Collection(REGISTERED_ISSUERS_COLLECTION).Doc(current.docID), []firestore.Update{
+			{Path: "issuer", Value: updated.Issuer},
+			{Path: "operator_name", Value: updated.OperatorName},
+			{Path: "software_provider", Value: updated.SoftwareProvider},
+			{Path: "private_parking", Value: updated.PrivateParking},
+			{Path: "aliases", Value: updated.Aliases},
+			{Path: "group_id", Value: updated.GroupID},
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(fs.Collection(REGISTERED_ISSUERS_AUDIT_COLLECTION).NewDoc(), AuditRecord{
+			T360ID:    ri.T360ID,
+			Action:    AuditUpdated,
+			Actor:     actor,
+			Changes:   changes,
+			ChangedAt: time.Now(),
+		})
+	})
+
+	if err != nil && !errors.Is(err, ErrIssuerNotFound) && !errors.Is(err, ErrOperatorNameInUse) {
+		log.Errorf("UpdateIssuer:[%s]:%v", ri.T360ID, err)
+	}
+
+	return err
+}
+
+// DeactivateIssuer marks the issuer as deactivated so it no longer matches name lookups, the document is kept for
+// historic searches and notices
+func DeactivateIssuer(ctx context.Context, t360ID string, actor string, reason string, fs *firestore.Client) error {
+
+	if len(strings.TrimSpace(actor)) == 0 {
+		return ErrMissingActor
+	}
+
+	err := fs.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+
+		existing, err := registeredIssuersInTx(tx, fs)
+		if err != nil {
+			return err
+		}
+
+		current, changes, err := planDeactivate(t360ID, existing)
+		if err != nil || len(changes) == 0 {
+			return err
+		}
+
+		err = tx.Update(fs.Collection(REGISTERED_ISSUERS_COLLECTION).Doc(current.docID), []firestore.Update{
+			{Path: "deactivated", Value: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(fs.Collection(REGISTERED_ISSUERS_AUDIT_COLLECTION).NewDoc(), AuditRecord{
+			T360ID:    t360ID,
+			Action:    AuditDeactivated,
+			Actor:     actor,
+			Reason:    reason,
+			Changes:   changes,
+			ChangedAt: time.Now(),
+		})
+	})
+
+	if err != nil && !errors.Is(err, ErrIssuerNotFound) {
+		log.Errorf("DeactivateIssuer:[%s]:%v", t360ID, err)
+	}
+
+	return err
+}
+
+// SetIssuerGroup moves the issuer into the group with the given id, an empty groupID removes it from its group
+func SetIssuerGroup(ctx context.Context, t360ID string, groupID string, actor string, fs *firestore.Client) error {
+
+	if len(strings.TrimSpace(actor)) == 0 {
+		return ErrMissingActor
+	}
+
+	groupID = strings.TrimSpace(groupID)
+	if len(groupID) > 0 {
+		if _, err := GetGroup(ctx, groupID, fs); err != nil {
+			return err
+		}
+	}
+
+	err := fs.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+
+		existing, err := registeredIssuersInTx(tx, fs)
+		if err != nil {
+			return err
+		}
+
+		current, ok := findByT360ID(t360ID, existing)
+		if !ok {
+			return fmt.Errorf("%w with t360 id %s", ErrIssuerNotFound, t360ID)
+		}
+		if current.GroupID == groupID {
+			return nil
+		}
+
+		err = tx.Update(fs.Collection(REGISTERED_ISSUERS_COLLECTION).Doc(current.docID), []firestore.Update{
+			{Path: "group_id", Value: groupID},
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(fs.Collection(REGISTERED_ISSUERS_AUDIT_COLLECTION).NewDoc(), AuditRecord{
+			T360ID:    t360ID,
+			Action:    AuditUpdated,
+			Actor:     actor,
+			Changes:   []FieldChange{{Field: "group_id", From: current.GroupID, To: groupID}},
+			ChangedAt: time.Now(),
+		})
+	})
+
+	if err != nil && !errors.Is(err, ErrIssuerNotFound) {
+		log.Errorf("SetIssuerGroup:[%s]:%v", t360ID, err)
+	}
+
+	return err
+}
+
+// ListIssuers returns the registered issuers ordered by t360 id
+func ListIssuers(ctx context.Context, includeDeactivated bool, fs *firestore.Client) ([]RegisteredIssuer, error) {
+
+	var issuers []RegisteredIssuer
+
+	itr := fs.Collection(REGISTERED_ISSUERS_COLLECTION).OrderBy("t360_id", firestore.Asc).Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			log.Errorf("ListIssuers:%v", err)
+			return nil, err
+		}
+
+		ri := RegisteredIssuer{}
+		if err = doc.DataTo(&ri); err != nil {
+			log.Errorf("ListIssuers:[%s]:%v", doc.Ref.ID, err)
+			return nil, err
+		}
+		ri.docID = doc.Ref.ID
+		issuers = append(issuers, ri)
+	}
+
+	return listed(issuers, includeDeactivated), nil
+}
+
+// listed drops deactivated issuers unless they were asked for
+func listed(issuers []RegisteredIssuer, includeDeactivated bool) []RegisteredIssuer {
+
+	if includeDeactivated {
+		return issuers
+	}
+
+	var active []RegisteredIssuer
+	for _, ri := range issuers {
+		if !ri.Deactivated {
+			active = append(active, ri)
+		}
+	}
+	return active
+}
+
+// IssuerAudit returns the audit trail for an issuer, oldest change first
+func IssuerAudit(ctx context.Context, t360ID string, fs *firestore.Client) ([]AuditRecord, error) {
+
+	var records []AuditRecord
+
+	itr := fs.Collection(REGISTERED_ISSUERS_AUDIT_COLLECTION).Where("t360_id", "==", t360ID).OrderBy("changed_at", firestore.Asc).Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			log.Errorf("IssuerAudit:[%s]:%v", t360ID, err)
+			return nil, err
+		}
+
+		ar := AuditRecord{}
+		if err = doc.DataTo(&ar); err != nil {
+			log.Errorf("IssuerAudit:[%s]:%v", t360ID, err)
+			return nil, err
+		}
+		records = append(records, ar)
+	}
+
+	return records, nil
+}
+
+func registeredIssuersInTx(tx *firestore.Transaction, fs *firestore.Client) ([]RegisteredIssuer, error) {
+
+	docs, err := tx.Documents(fs.Collection(REGISTERED_ISSUERS_COLLECTION)).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	issuers := make([]RegisteredIssuer, 0, len(docs))
+	for _, doc := range docs {
+		ri := RegisteredIssuer{}
+		if err = doc.DataTo(&ri); err != nil {
+			return nil, err
+		}
+		ri.docID = doc.Ref.ID
+		issuers = append(issuers, ri)
+	}
+
+	return issuers, nil
+}
+
+func findByT360ID(t360ID string, issuers []RegisteredIssuer) (RegisteredIssuer, bool) {
+	for _, ri := range issuers {
+		if ri.T360ID == t360ID {
+			return ri, true
+		}
+	}
+	return RegisteredIssuer{}, false
+}
+
+// planCreate checks a new issuer against the registry and returns the changes recorded in its audit record
+func planCreate(ri RegisteredIssuer, existing []RegisteredIssuer) ([]FieldChange, error) {
+
+	if err := checkUnique(ri, existing, true); err != nil {
+		return nil, err
+	}
+
+	return diffIssuers(RegisteredIssuer{}, ri), nil
+}
+
+// planUpdate returns the issuer being updated, the issuer as it will be after the update and the changes between
+// them, no changes means nothing needs writing. The deactivated flag is kept and an empty GroupID keeps the group.
+func planUpdate(ri RegisteredIssuer, existing []RegisteredIssuer) (current RegisteredIssuer, updated RegisteredIssuer, changes []FieldChange, err error) {
+
+	current, ok := findByT360ID(ri.T360ID, existing)
+	if !ok {
+		return current, ri, nil, fmt.Errorf("%w with t360 id %s", ErrIssuerNotFound, ri.T360ID)
+	}
+
+	ri.Deactivated = current.Deactivated
+	if len(ri.GroupID) == 0 {
+		ri.GroupID = current.GroupID
+	}
+
+	if err = checkUnique(ri, existing, false); err != nil {
+		return current, ri, nil, err
+	}
+
+	return current, ri, diffIssuers(current, ri), nil
+}
+
+// planDeactivate returns the issuer to deactivate and the change to record, no change if it is already deactivated
+func planDeactivate(t360ID string, existing []RegisteredIssuer) (RegisteredIssuer, []FieldChange, error) {
+
+	current, ok := findByT360ID(t360ID, existing)
+	if !ok {
+		return current, nil, fmt.Errorf("%w with t360 id %s", ErrIssuerNotFound, t360ID)
+	}
+	if current.Deactivated {
+		return current, nil, nil
+	}
+
+	return current, []FieldChange{{Field: "deactivated", From: false, To: true}}, nil
+}
+
+// checkUnique enforces a unique t360 id across all issuers, and a unique normalised operator name across active ones.
+// When updating, the issuer's own document is matched by t360 id and skipped.
+func checkUnique(ri RegisteredIssuer, existing []RegisteredIssuer, creating bool) error {
+
+	current, found := findByT360ID(ri.T360ID, existing)
+	if creating && found {
+		return fmt.Errorf("%w [%s]", ErrIssuerAlreadyExists, ri.T360ID)
+	}
+
+	name := NormaliseName(ri.OperatorName)
+	for _, e := range existing {
+		if e.Deactivated || (found && e.docID == current.docID) {
+			continue
+		}
+		if NormaliseName(e.OperatorName) == name {
+			return fmt.Errorf("%w [%s] by %s", ErrOperatorNameInUse, ri.OperatorName, e.T360ID)
+		}
+	}
+
+	return nil
+}
+
+func diffIssuers(before, after RegisteredIssuer) []FieldChange {
+
+	var changes []FieldChange
+	add := func(field string, from, to interface{}) {
+		if !reflect.DeepEqual(from, to) {
+			changes = append(changes, FieldChange{Field: field, From: from, To: to})
+		}
+	}
+
+	add("t360_id", before.T360ID, after.T360ID)
+	add("issuer", before.Issuer, after.Issuer)
+	add("operator_name", before.OperatorName, after.OperatorName)
+	add("software_provider", before.SoftwareProvider, after.SoftwareProvider)
+	add("private_parking", before.PrivateParking, after.PrivateParking)
+	if len(before.Aliases) > 0 || len(after.Aliases) > 0 {
+		add("aliases", before.Aliases, after.Aliases)
+	}
+	add("group_id", before.GroupID, after.GroupID)
+	add("deactivated", before.Deactivated, after.Deactivated)
+
+	return changes
+}
@@ -0,0 +1,205 @@
+package issuers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegisteredIssuerValidate(t *testing.T) {
+
+	ri := RegisteredIssuer{
+		T360ID:       " T1 ",
+		Issuer:       " Alpha Parking ",
+		OperatorName: "ALPHA PARKING LTD\t",
+		Aliases:      []string{" ALPHA ", "", "  "},
+		GroupID:      " G1 ",
+	}
+	if err := ri.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := RegisteredIssuer{T360ID: "T1", Issuer: "Alpha Parking", OperatorName: "ALPHA PARKING LTD", Aliases: []string{"ALPHA"}, GroupID: "G1"}
+	if !reflect.DeepEqual(ri, want) {
+		t.Errorf("got %+v, want %+v", ri, want)
+	}
+
+	tests := []struct {
+		name string
+		ri   RegisteredIssuer
+	}{
+		{"missing t360 id", RegisteredIssuer{T360ID: "  ", Issuer: "Alpha", OperatorName: "ALPHA"}},
+		{"missing issuer", RegisteredIssuer{T360ID: "T1", OperatorName: "ALPHA"}},
+		{"missing operator name", RegisteredIssuer{T360ID: "T1", Issuer: "Alpha", OperatorName: " "}},
+		{"negative software provider", RegisteredIssuer{T360ID: "T1", Issuer: "Alpha", OperatorName: "ALPHA", SoftwareProvider: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.ri.Validate(); err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
+}
+
+func registry() []RegisteredIssuer {
+	return []RegisteredIssuer{
+		{T360ID: "T1", Issuer: "Alpha Parking", OperatorName: "ALPHA PARKING LTD", GroupID: "G1", docID: "d1"},
+		{T360ID: "T2", Issuer: "Beta Parking", OperatorName: "BETA PARKING LIMITED", docID: "d2"},
+		{T360ID: "T3", Issuer: "Gamma Parking", OperatorName: "GAMMA PARKING", Deactivated: true, docID: "d3"},
+	}
+}
+
+func TestCheckUnique(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		ri       RegisteredIssuer
+		creating bool
+		want     error
+	}{
+		{"new issuer", RegisteredIssuer{T360ID: "T4", OperatorName: "DELTA PARKING"}, true, nil},
+		{"t360 id already exists", RegisteredIssuer{T360ID: "T2", OperatorName: "DELTA PARKING"}, true, ErrIssuerAlreadyExists},
+		{"operator name in use", RegisteredIssuer{T360ID: "T4", OperatorName: "Beta Parking Ltd"}, true, ErrOperatorNameInUse},
+		{"name of a deactivated issuer", RegisteredIssuer{T360ID: "T4", OperatorName: "Gamma Parking Limited"}, true, nil},
+		{"update keeping its own name", RegisteredIssuer{T360ID: "T1", OperatorName: "Alpha Parking Limited"}, false, nil},
+		{"update taking another name", RegisteredIssuer{T360ID: "T1", OperatorName: "BETA PARKING"}, false, ErrOperatorNameInUse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUnique(tt.ri, registry(), tt.creating)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffIssuers(t *testing.T) {
+
+	before := RegisteredIssuer{T360ID: "T1", Issuer: "Alpha", OperatorName: "ALPHA", SoftwareProvider: 1}
+
+	tests := []struct {
+		name   string
+		before RegisteredIssuer
+		after  RegisteredIssuer
+		want   []FieldChange
+	}{
+		{"unchanged", before, before, nil},
+		{"docID is not a field", before, RegisteredIssuer{T360ID: "T1", Issuer: "Alpha", OperatorName: "ALPHA", SoftwareProvider: 1, docID: "d1"}, nil},
+		{"nil and empty aliases are the same", before, RegisteredIssuer{T360ID: "T1", Issuer: "Alpha", OperatorName: "ALPHA", SoftwareProvider: 1, Aliases: []string{}}, nil},
+		{
+			"several fields",
+			before,
+			RegisteredIssuer{T360ID: "T1", Issuer: "Alpha", OperatorName: "ALPHA LTD", SoftwareProvider: 2, Aliases: []string{"A"}, PrivateParking: true},
+			[]FieldChange{
+				{Field: "operator_name", From: "ALPHA", To: "ALPHA LTD"},
+				{Field: "software_provider", From: 1, To: 2},
+				{Field: "private_parking", From: false, To: true},
+				{Field: "aliases", From: []string(nil), To: []string{"A"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffIssuers(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanCreate(t *testing.T) {
+
+	changes, err := planCreate(RegisteredIssuer{T360ID: "T4", Issuer: "Delta", OperatorName: "DELTA"}, registry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FieldChange{
+		{Field: "t360_id", From: "", To: "T4"},
+		{Field: "issuer", From: "", To: "Delta"},
+		{Field: "operator_name", From: "", To: "DELTA"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got %+v, want %+v", changes, want)
+	}
+
+	if _, err = planCreate(RegisteredIssuer{T360ID: "T1", Issuer: "Alpha", OperatorName: "OTHER"}, registry()); !errors.Is(err, ErrIssuerAlreadyExists) {
+		t.Errorf("got %v, want %v", err, ErrIssuerAlreadyExists)
+	}
+}
+
+func TestPlanUpdate(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		ri      RegisteredIssuer
+		wantErr error
+		want    []FieldChange
+	}{
+		{"unknown issuer", RegisteredIssuer{T360ID: "T9", Issuer: "Nine", OperatorName: "NINE"}, ErrIssuerNotFound, nil},
+		{"no changes keeps the group", RegisteredIssuer{T360ID: "T1", Issuer: "Alpha Parking", OperatorName: "ALPHA PARKING LTD"}, nil, nil},
+		{"name clash", RegisteredIssuer{T360ID: "T1", Issuer: "Alpha Parking", OperatorName: "BETA PARKING"}, ErrOperatorNameInUse, nil},
+		{
+			"group and name changed",
+			RegisteredIssuer{T360ID: "T1", Issuer: "Alpha Parking", OperatorName: "ALPHA PARKING", GroupID: "G2"},
+			nil,
+			[]FieldChange{
+				{Field: "operator_name", From: "ALPHA PARKING LTD", To: "ALPHA PARKING"},
+				{Field: "group_id", From: "G1", To: "G2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, _, changes, err := planUpdate(tt.ri, registry())
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && current.docID != "d1" {
+				t.Errorf("expected the current issuer d1, got %q", current.docID)
+			}
+			if !reflect.DeepEqual(changes, tt.want) {
+				t.Errorf("got %+v, want %+v", changes, tt.want)
+			}
+		})
+	}
+
+	// an update can't reactivate an issuer
+	_, updated, changes, err := planUpdate(RegisteredIssuer{T360ID: "T3", Issuer: "Gamma Parking", OperatorName: "GAMMA PARKING"}, registry())
+	if err != nil || !updated.Deactivated || changes != nil {
+		t.Errorf("expected the deactivated flag to be kept, got %+v %+v %v", updated, changes, err)
+	}
+}
+
+func TestPlanDeactivate(t *testing.T) {
+
+	current, changes, err := planDeactivate("T2", registry())
+	if err != nil || current.docID != "d2" || len(changes) != 1 || changes[0].Field != "deactivated" {
+		t.Errorf("expected T2 to be deactivated, got %+v %+v %v", current, changes, err)
+	}
+
+	if _, changes, err = planDeactivate("T3", registry()); err != nil || changes != nil {
+		t.Errorf("expected no change for a deactivated issuer, got %+v %v", changes, err)
+	}
+
+	if _, _, err = planDeactivate("T9", registry()); !errors.Is(err, ErrIssuerNotFound) {
+		t.Errorf("got %v, want %v", err, ErrIssuerNotFound)
+	}
+}
+
+func TestListed(t *testing.T) {
+
+	if got := listed(registry(), true); len(got) != 3 {
+		t.Errorf("expected every issuer, got %d", len(got))
+	}
+
+	got := listed(registry(), false)
+	if len(got) != 2 || got[0].T360ID != "T1" || got[1].T360ID != "T2" {
+		t.Errorf("expected the active issuers T1 and T2, got %+v", got)
+	}
+}
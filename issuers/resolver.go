@@ -0,0 +1,211 @@
+package issuers
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// DefaultResolverTTL - how long a resolved issuer is cached when NewResolver is given a zero TTL
+const DefaultResolverTTL = 10 * time.Minute
+
+// ErrNoIdentifiers - error raised when Resolve is called without any identifiers to look up
+var ErrNoIdentifiers = errors.New("no issuer identifiers supplied")
+
+// MatchPath - which lookup found the issuer
+type MatchPath string
+
+const (
+	MatchedT360ID          MatchPath = "t360_id"
+	MatchedSearchReference MatchPath = "search_reference"
+	MatchedOperatorName    MatchPath = "operator_name"
+	MatchedIssuerName      MatchPath = "issuer_name"
+	MatchedNormalisedName  MatchPath = "normalised_name"
+	MatchedSiteName        MatchPath = "site_name"
+)
+
+// Identifiers - whatever is known about the issuer, empty fields are skipped. Lookups are tried in the order
+// T360ID, Sref, OperatorName and then IssuerName, the first to find an issuer wins.
+type Identifiers struct {
+	T360ID       string
+	Sref         string
+	OperatorName string
+	IssuerName   string
+}
+
+// Resolution - the issuer found by a Resolver and how it was found
+type Resolution struct {
+	IssuerInformation
+	// MatchedBy - the lookup which found the issuer
+	MatchedBy MatchPath `json:"matched_by"`
+	// MatchedValue - the identifier value which was looked up
+	MatchedValue string `json:"matched_value"`
+	// Tried - every lookup attempted, in order, including the one which matched
+	Tried []MatchPath `json:"tried"`
+	// Cached - the result came from the resolver's cache
+	Cached bool `json:"cached"`
+}
+
+type cachedResolution struct {
+	resolution Resolution
+	expires    time.Time
+}
+
+// Resolver finds an issuer from any combination of identifiers, caching results in memory
+type Resolver struct {
+	fs    *firestore.Client
+	ttl   time.Duration
+	mu    sync.Mutex
+	cache map[string]cachedResolution
+	now   func() time.Time
+	// find runs a single lookup, lookup against firestore unless replaced in tests
+	find func(ctx context.Context, path MatchPath, value string) (IssuerInformation, MatchPath, error)
+}
+
+// NewResolver creates a Resolver, a ttl of 0 uses DefaultResolverTTL and a negative ttl disables caching
+func NewResolver(fs *firestore.Client, ttl time.Duration) *Resolver {
+
+	if ttl == 0 {
+		ttl = DefaultResolverTTL
+	}
+
+	r := &Resolver{
+		fs:    fs,
+		ttl:   ttl,
+		cache: map[string]cachedResolution{},
+		now:   time.Now,
+	}
+	r.find = r.lookup
+
+	return r
+}
+
+// Resolve looks up the issuer using the identifiers in precedence order, moving to the next identifier only when the
+// previous one was not found. Any other error stops the lookup.
+func (r *Resolver) Resolve(ctx context.Context, ids Identifiers) (Resolution, error) {
+
+	type step struct {
+		path  MatchPath
+		value string
+	}
+
+	steps := []step{
+		{MatchedT360ID, ids.T360ID},
+		{MatchedSearchReference, ids.Sref},
+		{MatchedOperatorName, ids.OperatorName},
+		{MatchedIssuerName, ids.IssuerName},
+	}
+
+	var tried []MatchPath
+	for _, s := range steps {
+		if len(s.value) == 0 {
+			continue
+		}
+		tried = append(tried, s.path)
+
+		if res, ok := r.fromCache(s.path, s.value); ok {
+			res.Tried = tried
+			return res, nil
+		}
+
+		info, matched, err := r.find(ctx, s.path, s.value)
+		if err != nil {
+			if errors.Is(err, ErrIssuerNotFound) {
+				continue
+			}
+			log.Errorf("Resolver.Resolve:[%s:%s]:%v", s.path, s.value, err)
+			return Resolution{Tried: tried}, err
+		}
+		if matched != s.path {
+			tried = append(tried, matched)
+		}
+
+		res := Resolution{
+			IssuerInformation: info,
+			MatchedBy:         matched,
+			MatchedValue:      s.value,
+		}
+		r.toCache(s.path, s.value, res)
+
+		res.Tried = tried
+		return res, nil
+	}
+
+	if len(tried) == 0 {
+		return Resolution{}, ErrNoIdentifiers
+	}
+
+	return Resolution{Tried: tried}, fmt.Errorf("%w using %v", ErrIssuerNotFound, tried)
+}
+
+// Forget empties the cache
+func (r *Resolver) Forget() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = map[string]cachedResolution{}
+}
+
+func (r *Resolver) lookup(ctx context.Context, path MatchPath, value string) (IssuerInformation, MatchPath, error) {
+
+	switch path {
+	case MatchedT360ID:
+		info, err := GetIssuerInformationFromT360ID(ctx, value, r.fs)
+		if err == nil && len(info.T360ID) == 0 {
+			err = fmt.Errorf("%w with t360 id %s", ErrIssuerNotFound, value)
+		}
+		return info, path, err
+	case MatchedSearchReference:
+		info, err := FromSearchReference(ctx, value, r.fs)
+		if err == nil && len(info.T360ID) == 0 {
+			err = fmt.Errorf("%w with search ref [%s]", ErrIssuerNotFound, value)
+		}
+		return info, path, err
+	case MatchedOperatorName:
+		return resolveOperatorName(ctx, value, r.fs)
+	case MatchedIssuerName:
+		info, err := FromIssuerName(ctx, value, r.fs)
+		return info, path, err
+	}
+
+	return IssuerInformation{}, "", fmt.Errorf("unknown lookup %s", path)
+}
+
+func (r *Resolver) fromCache(path MatchPath, value string) (Resolution, bool) {
+
+	if r.ttl < 0 {
+		return Resolution{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := string(path) + ":" + value
+	c, ok := r.cache[key]
+	if !ok {
+		return Resolution{}, false
+	}
+	if r.now().After(c.expires) {
+		delete(r.cache, key)
+		return Resolution{}, false
+	}
+
+	res := c.resolution
+	res.Cached = true
+	return res, true
+}
+
+func (r *Resolver) toCache(path MatchPath, value string, res Resolution) {
+
+	if r.ttl < 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cache[string(path)+":"+value] = cachedResolution{resolution: res, expires: r.now().Add(r.ttl)}
+}
@@ -0,0 +1,206 @@
+package issuers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeLookups answers resolver lookups from a map keyed on path and value, counting every call
+type fakeLookups struct {
+	found map[string]IssuerInformation
+	// matched - the path reported for a lookup when it differs from the one asked for
+	matched map[string]MatchPath
+	failing map[string]error
+	calls   []string
+}
+
+func (f *fakeLookups) find(_ context.Context, path MatchPath, value string) (IssuerInformation, MatchPath, error) {
+
+	key := string(path) + ":" + value
+	f.calls = append(f.calls, key)
+
+	if err, ok := f.failing[key]; ok {
+		return IssuerInformation{}, "", err
+	}
+	info, ok := f.found[key]
+	if !ok {
+		return IssuerInformation{}, "", fmt.Errorf("%w %s", ErrIssuerNotFound, key)
+	}
+	if m, ok := f.matched[key]; ok {
+		return info, m, nil
+	}
+	return info, path, nil
+}
+
+func testResolver(f *fakeLookups, ttl time.Duration, now *time.Time) *Resolver {
+	r := NewResolver(nil, ttl)
+	r.find = f.find
+	r.now = func() time.Time { return *now }
+	return r
+}
+
+func TestResolverPrecedence(t *testing.T) {
+
+	alpha := IssuerInformation{T360ID: "T1", Issuer: "Alpha"}
+	beta := IssuerInformation{T360ID: "T2", Issuer: "Beta"}
+	errBackend := errors.New("firestore unavailable")
+
+	tests := []struct {
+		name      string
+		ids       Identifiers
+		wantID    string
+		wantBy    MatchPath
+		wantValue string
+		wantTried []MatchPath
+		wantErr   error
+	}{
+		{
+			name:      "t360 id wins over everything else",
+			ids:       Identifiers{T360ID: "T1", Sref: "S2", OperatorName: "BETA"},
+			wantID:    "T1",
+			wantBy:    MatchedT360ID,
+			wantValue: "T1",
+			wantTried: []MatchPath{MatchedT360ID},
+		},
+		{
+			name:      "unknown t360 id falls through to the search reference",
+			ids:       Identifiers{T360ID: "T9", Sref: "S2", OperatorName: "ALPHA"},
+			wantID:    "T2",
+			wantBy:    MatchedSearchReference,
+			wantValue: "S2",
+			wantTried: []MatchPath{MatchedT360ID, MatchedSearchReference},
+		},
+		{
+			name:      "empty identifiers are skipped",
+			ids:       Identifiers{IssuerName: "Beta"},
+			wantID:    "T2",
+			wantBy:    MatchedIssuerName,
+			wantValue: "Beta",
+			wantTried: []MatchPath{MatchedIssuerName},
+		},
+		{
+			name:      "operator name lookup reports the path which matched",
+			ids:       Identifiers{OperatorName: "Alpha Ltd"},
+			wantID:    "T1",
+			wantBy:    MatchedNormalisedName,
+			wantValue: "Alpha Ltd",
+			wantTried: []MatchPath{MatchedOperatorName, MatchedNormalisedName},
+		},
+		{
+			name:      "nothing found",
+			ids:       Identifiers{T360ID: "T9", IssuerName: "Nobody"},
+			wantTried: []MatchPath{MatchedT360ID, MatchedIssuerName},
+			wantErr:   ErrIssuerNotFound,
+		},
+		{
+			name:      "other errors stop the lookup",
+			ids:       Identifiers{Sref: "BROKEN", IssuerName: "Beta"},
+			wantTried: []MatchPath{MatchedSearchReference},
+			wantErr:   errBackend,
+		},
+		{
+			name:    "no identifiers",
+			ids:     Identifiers{},
+			wantErr: ErrNoIdentifiers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			f := &fakeLookups{
+				found: map[string]IssuerInformation{
+					"t360_id:T1":              alpha,
+					"search_reference:S2":     beta,
+					"operator_name:BETA":      beta,
+					"operator_name:ALPHA":     alpha,
+					"operator_name:Alpha Ltd": alpha,
+					"issuer_name:Beta":        beta,
+				},
+				matched: map[string]MatchPath{"operator_name:Alpha Ltd": MatchedNormalisedName},
+				failing: map[string]error{"search_reference:BROKEN": errBackend},
+			}
+			now := time.Now()
+
+			res, err := testResolver(f, 0, &now).Resolve(context.Background(), tt.ids)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if res.T360ID != tt.wantID || res.MatchedBy != tt.wantBy || res.MatchedValue != tt.wantValue {
+				t.Errorf("got %s by %s [%s], want %s by %s [%s]", res.T360ID, res.MatchedBy, res.MatchedValue, tt.wantID, tt.wantBy, tt.wantValue)
+			}
+			if !reflect.DeepEqual(res.Tried, tt.wantTried) {
+				t.Errorf("tried %v, want %v", res.Tried, tt.wantTried)
+			}
+			if res.Cached {
+				t.Error("first resolution should not be cached")
+			}
+		})
+	}
+}
+
+func TestResolverCache(t *testing.T) {
+
+	f := &fakeLookups{found: map[string]IssuerInformation{"t360_id:T1": {T360ID: "T1"}}}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	r := testResolver(f, time.Minute, &now)
+	ids := Identifiers{T360ID: "T1"}
+
+	resolve := func(wantCached bool, wantCalls int) {
+		t.Helper()
+		res, err := r.Resolve(context.Background(), ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Cached != wantCached {
+			t.Errorf("cached %v, want %v", res.Cached, wantCached)
+		}
+		if len(f.calls) != wantCalls {
+			t.Errorf("%d lookups, want %d", len(f.calls), wantCalls)
+		}
+	}
+
+	resolve(false, 1)
+
+	now = now.Add(time.Minute)
+	resolve(true, 1)
+
+	// expired once the ttl has passed
+	now = now.Add(time.Second)
+	resolve(false, 2)
+
+	r.Forget()
+	resolve(false, 3)
+
+	// misses are never cached
+	if _, err := r.Resolve(context.Background(), Identifiers{T360ID: "T9"}); !errors.Is(err, ErrIssuerNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrIssuerNotFound)
+	}
+	if _, err := r.Resolve(context.Background(), Identifiers{T360ID: "T9"}); !errors.Is(err, ErrIssuerNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrIssuerNotFound)
+	}
+	if len(f.calls) != 5 {
+		t.Errorf("%d lookups, want 5", len(f.calls))
+	}
+}
+
+func TestResolverCacheDisabled(t *testing.T) {
+
+	f := &fakeLookups{found: map[string]IssuerInformation{"t360_id:T1": {T360ID: "T1"}}}
+	now := time.Now()
+	r := testResolver(f, -1, &now)
+
+	for i := 0; i < 2; i++ {
+		res, err := r.Resolve(context.Background(), Identifiers{T360ID: "T1"})
+		if err != nil || res.Cached {
+			t.Fatalf("expected an uncached resolution, got %+v %v", res, err)
+		}
+	}
+	if len(f.calls) != 2 {
+		t.Errorf("%d lookups, want 2", len(f.calls))
+	}
+}
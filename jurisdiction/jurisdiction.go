@@ -0,0 +1,132 @@
+// Package jurisdiction holds the rules that differ between England and Wales, Scotland and Northern Ireland for
+// parking charge notices. Keeper and hirer liability under Schedule 4 of the Protection of Freedoms Act 2012 only
+// exists in England and Wales, in Scotland and Northern Ireland only the driver can be pursued.
+package jurisdiction
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/transfer360/go-transfer360/calendar"
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
+var ErrUnsupportedJurisdiction = errors.New("notices are not supported in jurisdiction")
+var ErrJurisdictionMismatch = errors.New("jurisdiction does not match site postcode")
+
+// Rules - what a notice may rely on and say in a jurisdiction
+type Rules struct {
+	Jurisdiction postcode.Jurisdiction
+	// KeeperLiability - the keeper, and through them the hirer, can be held liable under POFA Schedule 4
+	KeeperLiability bool
+	// Calendar - working days used for deemed service
+	Calendar calendar.Calendar
+	// forbidden - wording which must not appear on a notice as it claims liability which does not exist
+	forbidden []*regexp.Regexp
+}
+
+var pofaWording = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)protection\s+of\s+freedoms\s+act`),
+	regexp.MustCompile(`(?i)\bPOFA\b`),
+	regexp.MustCompile(`(?i)schedule\s+4`),
+	regexp.MustCompile(`(?i)keeper\s+liability`),
+	regexp.MustCompile(`(?i)hirer\s+liability`),
+	regexp.MustCompile(`(?i)(keeper|hirer)\s+(is|will\s+be|may\s+be|are)\s+(held\s+)?liable`),
+}
+
+// Parse returns the jurisdiction for an explicit name such as "scotland", "NI" or "england_and_wales"
+func Parse(name string) (postcode.Jurisdiction, error) {
+
+	switch strings.ToUpper(strings.NewReplacer("-", " ", "_", " ", "&", "AND").Replace(strings.TrimSpace(name))) {
+	case "ENGLAND AND WALES", "ENGLAND", "WALES", "E AND W", "EW":
+		return postcode.EnglandAndWales, nil
+	case "SCOTLAND", "SCT":
+		return postcode.Scotland, nil
+	case "NORTHERN IRELAND", "NI":
+		return postcode.NorthernIreland, nil
+	}
+
+	return "", fmt.Errorf("%w [%s]", ErrUnsupportedJurisdiction, name)
+}
+
+// Detect returns the jurisdiction of a site from an explicit jurisdiction, the site postcode, or both. When both are
+// given they must agree. When neither is given England and Wales is assumed.
+func Detect(explicit string, sitePostcode string) (postcode.Jurisdiction, error) {
+
+	var fromName, fromPostcode postcode.Jurisdiction
+	var err error
+
+	if len(strings.TrimSpace(explicit)) > 0 {
+		if fromName, err = Parse(explicit); err != nil {
+			return "", err
+		}
+	}
+
+	if len(strings.TrimSpace(sitePostcode)) > 0 {
+		if fromPostcode, err = postcode.JurisdictionOf(sitePostcode); err != nil {
+			return "", err
+		}
+	}
+
+	switch {
+	case len(fromName) > 0 && len(fromPostcode) > 0 && fromName != fromPostcode:
+		return "", fmt.Errorf("%w: %s and %s [%s]", ErrJurisdictionMismatch, fromName, fromPostcode, sitePostcode)
+	case len(fromName) > 0:
+		return fromName, nil
+	case len(fromPostcode) > 0:
+		return fromPostcode, nil
+	}
+
+	return postcode.EnglandAndWales, nil
+}
+
+// RulesFor returns the notice rules for a jurisdiction, Crown Dependencies and BFPO addresses are not supported
+func RulesFor(j postcode.Jurisdiction) (Rules, error) {
+
+	cal, err := calendar.For(j)
+	if err != nil {
+		return Rules{}, fmt.Errorf("%w [%s]", ErrUnsupportedJurisdiction, j)
+	}
+
+	r := Rules{Jurisdiction: j, Calendar: cal}
+
+	switch j {
+	case postcode.EnglandAndWales:
+		r.KeeperLiability = true
+	case postcode.Scotland, postcode.NorthernIreland:
+		r.forbidden = pofaWording
+	default:
+		return Rules{}, fmt.Errorf("%w [%s]", ErrUnsupportedJurisdiction, j)
+	}
+
+	return r, nil
+}
+
+// CheckWording returns a warning for each phrase in the notice text which relies on liability that does not exist in
+// the jurisdiction
+func (r Rules) CheckWording(text string) []string {
+
+	var warnings []string
+	for _, re := range r.forbidden {
+		if m := re.FindString(text); len(m) > 0 {
+			warnings = append(warnings, fmt.Sprintf("notice wording %q relies on keeper or hirer liability which does not exist in %s", m, r.Name()))
+		}
+	}
+
+	return warnings
+}
+
+// Name returns the jurisdiction as it would be written on a notice
+func (r Rules) Name() string {
+	switch r.Jurisdiction {
+	case postcode.EnglandAndWales:
+		return "England and Wales"
+	case postcode.Scotland:
+		return "Scotland"
+	case postcode.NorthernIreland:
+		return "Northern Ireland"
+	}
+	return string(r.Jurisdiction)
+}
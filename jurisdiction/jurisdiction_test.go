@@ -0,0 +1,103 @@
+package jurisdiction
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
+func TestDetect(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		explicit string
+		postcode string
+		want     postcode.Jurisdiction
+		err      error
+	}{
+		{"nothing given", "", "", postcode.EnglandAndWales, nil},
+		{"explicit", "Scotland", "", postcode.Scotland, nil},
+		{"explicit alias", "ni", "", postcode.NorthernIreland, nil},
+		{"explicit with separators", "england-and-wales", "", postcode.EnglandAndWales, nil},
+		{"postcode", "", "EH1 1YZ", postcode.Scotland, nil},
+		{"berwick", "", "TD15 1BT", postcode.EnglandAndWales, nil},
+		{"both agree", "scotland", "G1 1XQ", postcode.Scotland, nil},
+		{"both disagree", "scotland", "LS1 4DY", "", ErrJurisdictionMismatch},
+		{"unknown name", "france", "", "", ErrUnsupportedJurisdiction},
+		{"partial postcode", "", "LS1", "", postcode.ErrInvalidPostcode},
+		{"crown dependency", "", "JE2 3QN", postcode.CrownDependency, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(tt.explicit, tt.postcode)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRulesFor(t *testing.T) {
+
+	tests := []struct {
+		jurisdiction    postcode.Jurisdiction
+		keeperLiability bool
+		name            string
+		err             error
+	}{
+		{postcode.EnglandAndWales, true, "England and Wales", nil},
+		{postcode.Scotland, false, "Scotland", nil},
+		{postcode.NorthernIreland, false, "Northern Ireland", nil},
+		{postcode.CrownDependency, false, "", ErrUnsupportedJurisdiction},
+		{postcode.BritishForcesPost, false, "", ErrUnsupportedJurisdiction},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.jurisdiction), func(t *testing.T) {
+			r, err := RulesFor(tt.jurisdiction)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if r.KeeperLiability != tt.keeperLiability || r.Name() != tt.name || r.Calendar.Jurisdiction() != tt.jurisdiction {
+				t.Errorf("got %+v named %s", r, r.Name())
+			}
+		})
+	}
+}
+
+func TestCheckWording(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		j        postcode.Jurisdiction
+		text     string
+		warnings int
+	}{
+		{"england allows pofa wording", postcode.EnglandAndWales, "Under Schedule 4 of the Protection of Freedoms Act the keeper is liable", 0},
+		{"scotland driver only", postcode.Scotland, "The driver is liable for this charge", 0},
+		{"scotland pofa", postcode.Scotland, "issued under the Protection of Freedoms Act 2012", 1},
+		{"northern ireland abbreviations", postcode.NorthernIreland, "POFA schedule 4 keeper liability applies", 3},
+		{"hirer will be held liable", postcode.Scotland, "The hirer will be held liable if unpaid", 1},
+		{"word inside another word", postcode.Scotland, "Pofaction street", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := RulesFor(tt.j)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.CheckWording(tt.text); len(got) != tt.warnings {
+				t.Errorf("got %d warnings %v, want %d", len(got), got, tt.warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"cloud.google.com/go/firestore"
+	"github.com/transfer360/go-transfer360/search"
+)
+
+// NormaliseSearchIssuerID moves the legacy client.issuerid field on search records to client.issuer_id, searches
+// already holding client.issuer_id keep that value and just lose the legacy field
+var NormaliseSearchIssuerID = Migration{
+	ID:          "0001_normalise_search_issuer_id",
+	Description: "move searches client.issuerid to client.issuer_id",
+	Collection:  search.SEARCHES_COLLECTION,
+	Transform: func(doc *firestore.DocumentSnapshot) ([]firestore.Update, error) {
+
+		client := struct {
+			Client struct {
+				IssuerID  *string `firestore:"issuerid"`
+				Issuer_ID string  `firestore:"issuer_id"`
+			} `firestore:"client"`
+		}{}
+
+		if err := doc.DataTo(&client); err != nil {
+			return nil, err
+		}
+
+		return issuerIDUpdates(client.Client.IssuerID, client.Client.Issuer_ID), nil
+	},
+}
+
+// issuerIDUpdates returns the updates for a search's legacy and current issuer ids, none once the legacy field is gone
+func issuerIDUpdates(legacy *string, current string) []firestore.Update {
+
+	if legacy == nil {
+		return nil
+	}
+
+	updates := []firestore.Update{{Path: "client.issuerid", Value: firestore.Delete}}
+	if len(current) == 0 && len(*legacy) > 0 {
+		updates = append(updates, firestore.Update{Path: "client.issuer_id", Value: *legacy})
+	}
+
+	return updates
+}
@@ -0,0 +1,307 @@
+// Package migrations applies ordered, resumable transformations to Firestore collections, recording each migration
+// in a metadata collection so it is only applied once
+package migrations
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
+	"sort"
+	"time"
+)
+
+const MIGRATIONS_COLLECTION = "schema_migrations"
+
+// DefaultBatchSize - documents read and written per transaction when the Runner batch size is not set, each batch
+// also writes the migration's progress so must stay under the Firestore limit of 500 writes
+const DefaultBatchSize = 400
+
+var ErrDuplicateMigration = errors.New("duplicate migration id")
+var ErrInvalidMigration = errors.New("migration is missing an id, collection or transform")
+
+// Status - progress of a migration held in the metadata collection
+type Status string
+
+const (
+	StatusRunning  Status = "running"
+	StatusComplete Status = "complete"
+)
+
+// Migration - a transformation applied to every document in a collection. Transform must be idempotent, returning no
+// updates for a document which is already in the new shape, so an interrupted migration can be resumed safely.
+type Migration struct {
+	// ID - unique id, migrations are run in ID order so prefix with a number, e.g. "0001_normalise_issuer_id"
+	ID          string
+	Description string
+	Collection  string
+	// Query - optional filter on the collection, the runner adds its own ordering and paging
+	Query func(col *firestore.CollectionRef) firestore.Query
+	// Transform - returns the updates for a document, or none if the document needs no change. It is called again
+	// with the document's new contents if the batch's transaction is retried.
+	Transform func(doc *firestore.DocumentSnapshot) ([]firestore.Update, error)
+}
+
+// Record - a document in the schema_migrations collection
+type Record struct {
+	ID           string    `json:"id" firestore:"id"`
+	Description  string    `json:"description" firestore:"description"`
+	Status       Status    `json:"status" firestore:"status"`
+	LastDocument string    `json:"last_document" firestore:"last_document"`
+	Scanned      int       `json:"scanned" firestore:"scanned"`
+	Changed      int       `json:"changed" firestore:"changed"`
+	StartedAt    time.Time `json:"started_at" firestore:"started_at"`
+	CompletedAt  time.Time `json:"completed_at,omitempty" firestore:"completed_at,omitempty"`
+}
+
+// Result - what a Run did for one migration
+type Result struct {
+	ID             string `json:"id"`
+	AlreadyApplied bool   `json:"already_applied"`
+	Resumed        bool   `json:"resumed"`
+	Scanned        int    `json:"scanned"`
+	Changed        int    `json:"changed"`
+	DryRun         bool   `json:"dry_run"`
+}
+
+// Runner applies migrations in ID order
+type Runner struct {
+	fs         *firestore.Client
+	migrations []Migration
+	// BatchSize - documents per transaction, 0 uses DefaultBatchSize
+	BatchSize int
+	// DryRun - scan and count the documents which would change without writing anything
+	DryRun bool
+}
+
+// NewRunner creates a Runner for the migrations, they are sorted by ID and checked for duplicates
+func NewRunner(fs *firestore.Client, migrations ...Migration) (*Runner, error) {
+
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	for i, m := range sorted {
+		if len(m.ID) == 0 || len(m.Collection) == 0 || m.Transform == nil {
+			return nil, fmt.Errorf("%w [%s]", ErrInvalidMigration, m.ID)
+		}
+		if i > 0 && sorted[i-1].ID == m.ID {
+			return nil, fmt.Errorf("%w [%s]", ErrDuplicateMigration, m.ID)
+		}
+	}
+
+	return &Runner{fs: fs, migrations: sorted}, nil
+}
+
+// Run applies every migration not yet complete, resuming any which were interrupted from the last document written
+func (r *Runner) Run(ctx context.Context) ([]Result, error) {
+
+	var results []Result
+	for _, m := range r.migrations {
+		res, err := r.apply(ctx, m)
+		results = append(results, res)
+		if err != nil {
+			log.Errorf("Runner.Run:[%s]:%v", m.ID, err)
+			return results, fmt.Errorf("migration %s: %w", m.ID, err)
+		}
+	}
+
+	return results, nil
+}
+
+// Applied returns the metadata records of every migration which has been started
+func Applied(ctx context.Context, fs *firestore.Client) ([]Record, error) {
+
+	var records []Record
+
+	itr := fs.Collection(MIGRATIONS_COLLECTION).OrderBy("id", firestore.Asc).Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			log.Errorf("Applied:%v", err)
+			return nil, err
+		}
+
+		rec := Record{}
+		if err = doc.DataTo(&rec); err != nil {
+			log.Errorf("Applied:[%s]:%v", doc.Ref.ID, err)
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+func (r *Runner) apply(ctx context.Context, m Migration) (Result, error) {
+
+	metaRef := r.fs.Collection(MIGRATIONS_COLLECTION).Doc(m.ID)
+
+	// a migration without a record has never been started
+	var existing *Record
+	snap, err := metaRef.Get(ctx)
+	if err != nil {
+		if snap == nil || snap.Exists() {
+			return Result{ID: m.ID, DryRun: r.DryRun}, err
+		}
+	} else {
+		existing = &Record{}
+		if err = snap.DataTo(existing); err != nil {
+			return Result{ID: m.ID, DryRun: r.DryRun}, err
+		}
+	}
+
+	rec, res := start(m, existing, r.DryRun, time.Now())
+	if res.AlreadyApplied {
+		return res, nil
+	}
+
+	batchSize := r.batchSize()
+
+	col := r.fs.Collection(m.Collection)
+	base := col.Query
+	if m.Query != nil {
+		base = m.Query(col)
+	}
+
+	for {
+		q := base.OrderBy(firestore.DocumentID, firestore.Asc).Limit(batchSize)
+		if len(rec.LastDocument) > 0 {
+			q = q.StartAfter(rec.LastDocument)
+		}
+
+		var docs []*firestore.DocumentSnapshot
+		var changes []change
+
+		if r.DryRun {
+			if docs, err = q.Documents(ctx).GetAll(); err != nil {
+				return res, err
+			}
+			if changes, err = transformBatch(m, docs); err != nil {
+				return res, err
+			}
+		} else {
+			// the batch is read inside the transaction so a document changed by someone else before the updates are
+			// committed makes Firestore retry the batch against its new contents
+			err = r.fs.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+
+				var err error
+				if docs, err = tx.Documents(q).GetAll(); err != nil {
+					return err
+				}
+				if len(docs) == 0 {
+					return nil
+				}
+				if changes, err = transformBatch(m, docs); err != nil {
+					return err
+				}
+
+				for _, c := range changes {
+					if err := tx.Update(c.ref, c.updates); err != nil {
+						return err
+					}
+				}
+
+				return tx.Set(metaRef, rec.advance(docs, changes))
+			})
+			if err != nil {
+				return res, err
+			}
+		}
+
+		if len(docs) == 0 {
+			break
+		}
+
+		res.Scanned += len(docs)
+		res.Changed += len(changes)
+		rec = rec.advance(docs, changes)
+
+		if len(docs) < batchSize {
+			break
+		}
+	}
+
+	if r.DryRun {
+		return res, nil
+	}
+
+	rec.Status = StatusComplete
+	rec.CompletedAt = time.Now()
+	_, err = metaRef.Set(ctx, rec)
+
+	return res, err
+}
+
+func (r *Runner) batchSize() int {
+	if r.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return r.BatchSize
+}
+
+// start returns the record to run a migration from and its initial result. existing is the migration's record, nil
+// if it has never been started. A complete migration is already applied, a running one is resumed from its last
+// document, except on a dry run which always scans from the start so the counts cover the whole collection.
+func start(m Migration, existing *Record, dryRun bool, now time.Time) (Record, Result) {
+
+	res := Result{ID: m.ID, DryRun: dryRun}
+
+	if existing == nil {
+		return Record{ID: m.ID, Description: m.Description, Status: StatusRunning, StartedAt: now}, res
+	}
+
+	rec := *existing
+	if rec.Status == StatusComplete {
+		res.AlreadyApplied = true
+		return rec, res
+	}
+	res.Resumed = true
+
+	if dryRun {
+		rec.LastDocument = ""
+	}
+
+	return rec, res
+}
+
+// advance returns the record after a batch of documents has been scanned and its changes written
+func (rec Record) advance(docs []*firestore.DocumentSnapshot, changes []change) Record {
+
+	if len(docs) == 0 {
+		return rec
+	}
+
+	rec.Scanned += len(docs)
+	rec.Changed += len(changes)
+	rec.LastDocument = docs[len(docs)-1].Ref.ID
+	return rec
+}
+
+type change struct {
+	ref     *firestore.DocumentRef
+	updates []firestore.Update
+}
+
+func transformBatch(m Migration, docs []*firestore.DocumentSnapshot) ([]change, error) {
+
+	var changes []change
+	for _, doc := range docs {
+		updates, err := m.Transform(doc)
+		if err != nil {
+			return nil, fmt.Errorf("transforming %s: %w", doc.Ref.ID, err)
+		}
+		if len(updates) > 0 {
+			changes = append(changes, change{doc.Ref, updates})
+		}
+	}
+
+	return changes, nil
+}
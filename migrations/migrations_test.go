@@ -0,0 +1,210 @@
+package migrations
+
+import (
+	"cloud.google.com/go/firestore"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func noop(*firestore.DocumentSnapshot) ([]firestore.Update, error) {
+	return nil, nil
+}
+
+func docs(ids ...string) []*firestore.DocumentSnapshot {
+	var d []*firestore.DocumentSnapshot
+	for _, id := range ids {
+		d = append(d, &firestore.DocumentSnapshot{Ref: &firestore.DocumentRef{ID: id}})
+	}
+	return d
+}
+
+func TestNewRunner(t *testing.T) {
+
+	r, err := NewRunner(nil,
+		Migration{ID: "0003_c", Collection: "c", Transform: noop},
+		Migration{ID: "0001_a", Collection: "c", Transform: noop},
+		Migration{ID: "0002_b", Collection: "c", Transform: noop},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, m := range r.migrations {
+		ids = append(ids, m.ID)
+	}
+	if want := []string{"0001_a", "0002_b", "0003_c"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got %v, want %v", ids, want)
+	}
+
+	tests := []struct {
+		name       string
+		migrations []Migration
+		want       error
+	}{
+		{"duplicate id", []Migration{{ID: "0002", Collection: "c", Transform: noop}, {ID: "0001", Collection: "c", Transform: noop}, {ID: "0002", Collection: "d", Transform: noop}}, ErrDuplicateMigration},
+		{"missing id", []Migration{{Collection: "c", Transform: noop}}, ErrInvalidMigration},
+		{"missing collection", []Migration{{ID: "0001", Transform: noop}}, ErrInvalidMigration},
+		{"missing transform", []Migration{{ID: "0001", Collection: "c"}}, ErrInvalidMigration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRunner(nil, tt.migrations...); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchSize(t *testing.T) {
+
+	if got := (&Runner{}).batchSize(); got != DefaultBatchSize {
+		t.Errorf("got %d, want %d", got, DefaultBatchSize)
+	}
+	if got := (&Runner{BatchSize: 50}).batchSize(); got != 50 {
+		t.Errorf("got %d, want 50", got)
+	}
+}
+
+func TestStart(t *testing.T) {
+
+	m := Migration{ID: "0001_a", Description: "a"}
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-time.Hour)
+
+	running := &Record{ID: "0001_a", Status: StatusRunning, LastDocument: "d9", Scanned: 9, Changed: 4, StartedAt: started}
+	complete := &Record{ID: "0001_a", Status: StatusComplete, LastDocument: "d20", Scanned: 20, Changed: 5, StartedAt: started, CompletedAt: now}
+
+	tests := []struct {
+		name     string
+		existing *Record
+		dryRun   bool
+		wantRec  Record
+		wantRes  Result
+	}{
+		{
+			name:    "never started",
+			wantRec: Record{ID: "0001_a", Description: "a", Status: StatusRunning, StartedAt: now},
+			wantRes: Result{ID: "0001_a"},
+		},
+		{
+			name:     "interrupted run resumes from the last document",
+			existing: running,
+			wantRec:  *running,
+			wantRes:  Result{ID: "0001_a", Resumed: true},
+		},
+		{
+			name:     "dry run of an interrupted run scans from the start",
+			existing: running,
+			dryRun:   true,
+			wantRec:  Record{ID: "0001_a", Status: StatusRunning, Scanned: 9, Changed: 4, StartedAt: started},
+			wantRes:  Result{ID: "0001_a", Resumed: true, DryRun: true},
+		},
+		{
+			name:     "complete migration is not run again",
+			existing: complete,
+			wantRec:  *complete,
+			wantRes:  Result{ID: "0001_a", AlreadyApplied: true},
+		},
+		{
+			name:     "complete migration on a dry run",
+			existing: complete,
+			dryRun:   true,
+			wantRec:  *complete,
+			wantRes:  Result{ID: "0001_a", AlreadyApplied: true, DryRun: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, res := start(m, tt.existing, tt.dryRun, now)
+			if !reflect.DeepEqual(rec, tt.wantRec) {
+				t.Errorf("record %+v, want %+v", rec, tt.wantRec)
+			}
+			if res != tt.wantRes {
+				t.Errorf("result %+v, want %+v", res, tt.wantRes)
+			}
+		})
+	}
+
+	// the existing record is not changed by a dry run
+	if running.LastDocument != "d9" {
+		t.Errorf("existing record changed to %q", running.LastDocument)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+
+	rec := Record{ID: "0001_a", Status: StatusRunning, LastDocument: "d2", Scanned: 2, Changed: 1}
+
+	got := rec.advance(docs("d3", "d4", "d5"), []change{{}, {}})
+	want := Record{ID: "0001_a", Status: StatusRunning, LastDocument: "d5", Scanned: 5, Changed: 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if rec.Scanned != 2 {
+		t.Error("advance changed the original record")
+	}
+
+	if got = rec.advance(nil, nil); !reflect.DeepEqual(got, rec) {
+		t.Errorf("an empty batch changed the record to %+v", got)
+	}
+}
+
+func TestTransformBatch(t *testing.T) {
+
+	m := Migration{
+		ID: "0001_a",
+		Transform: func(doc *firestore.DocumentSnapshot) ([]firestore.Update, error) {
+			switch doc.Ref.ID {
+			case "bad":
+				return nil, errors.New("unreadable")
+			case "d2":
+				return []firestore.Update{{Path: "x", Value: 1}}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	changes, err := transformBatch(m, docs("d1", "d2", "d3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].ref.ID != "d2" {
+		t.Errorf("expected only d2 to change, got %+v", changes)
+	}
+
+	if _, err = transformBatch(m, docs("d1", "bad")); err == nil {
+		t.Error("expected the transform error")
+	}
+}
+
+func TestIssuerIDUpdates(t *testing.T) {
+
+	legacy := "T1"
+	empty := ""
+
+	tests := []struct {
+		name    string
+		legacy  *string
+		current string
+		want    []firestore.Update
+	}{
+		{"already migrated", nil, "T1", nil},
+		{"no issuer at all", nil, "", nil},
+		{"legacy only", &legacy, "", []firestore.Update{{Path: "client.issuerid", Value: firestore.Delete}, {Path: "client.issuer_id", Value: "T1"}}},
+		{"both keep the current id", &legacy, "T2", []firestore.Update{{Path: "client.issuerid", Value: firestore.Delete}}},
+		{"empty legacy field is removed", &empty, "", []firestore.Update{{Path: "client.issuerid", Value: firestore.Delete}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issuerIDUpdates(tt.legacy, tt.current); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
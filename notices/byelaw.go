@@ -0,0 +1,29 @@
+package notices
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// Byelaw - a penalty under byelaws on land such as airports, railway stations and ports
+type Byelaw struct {
+	Common
+	// Byelaw - the byelaw breached, e.g. "Railway Byelaws 2005 s14"
+	Byelaw string `json:"byelaw" validate:"required"`
+	// Landowner - the body whose byelaws apply
+	Landowner string `json:"landowner" validate:"required"`
+}
+
+func (n *Byelaw) Type() Type       { return TypeByelaw }
+func (n *Byelaw) Endpoint() string { return "/notice/byelaw" }
+
+func (n *Byelaw) Validate() error {
+	if err := n.validateCommon(); err != nil {
+		return err
+	}
+	validate := validator.New()
+	return validate.Struct(n)
+}
+
+func init() {
+	Register(TypeByelaw, func() Notice { return &Byelaw{} })
+}
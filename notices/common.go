@@ -0,0 +1,68 @@
+package notices
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/go-playground/validator/v10"
+	"github.com/transfer360/go-transfer360/fingerprint"
+	"github.com/transfer360/go-transfer360/vrm"
+)
+
+// Common - fields shared by every notice type, amounts are in pence
+type Common struct {
+	SearchReference       string `json:"sref" validate:"required"`
+	NoticeNumber          string `json:"notice_number" validate:"required"`
+	VRM                   string `json:"vrm" validate:"required"`
+	VRMCountry            string `json:"vrm_country,omitempty"`
+	ContraventionDateTime string `json:"contravention_date_time" validate:"required"`
+	Location              string `json:"location" validate:"required"`
+	IssuedBy              string `json:"issued_by" validate:"required"`
+	Amount                int    `json:"amount" validate:"gt=0"`
+}
+
+// validateCommon checks the shared fields, normalising the registration and vehicle country
+func (c *Common) validateCommon() error {
+
+	if !govalidator.IsRFC3339(c.ContraventionDateTime) {
+		return fmt.Errorf("invalid ContraventionDatetime format, should be RFC3339 - please see documentation")
+	}
+
+	cDateTime, _ := time.Parse(time.RFC3339, c.ContraventionDateTime)
+	if cDateTime.After(time.Now()) {
+		return fmt.Errorf("invalid ContraventionDatetime is a future date - please see documentation")
+	}
+
+	country, err := vrm.NormaliseCountryCode(c.VRMCountry)
+	if err != nil {
+		return err
+	}
+
+	reg, err := vrm.Normalise(c.VRM, country)
+	if err != nil {
+		return err
+	}
+	c.VRM = reg
+
+	if len(c.VRMCountry) > 0 {
+		c.VRMCountry = country
+	}
+
+	validate := validator.New()
+	return validate.Struct(c)
+}
+
+// Fingerprint returns what identifies the notice for duplicate detection, the stay is the contravention time
+func (c *Common) Fingerprint() (fingerprint.Fingerprint, error) {
+
+	contravention, err := time.Parse(time.RFC3339, c.ContraventionDateTime)
+	if err != nil {
+		return fingerprint.Fingerprint{}, fmt.Errorf("invalid ContraventionDatetime: %w", err)
+	}
+
+	f := fingerprint.New(c.VRM, c.Location, c.NoticeNumber, contravention, time.Time{}, time.Time{})
+	f.SearchReference = c.SearchReference
+
+	return f, nil
+}
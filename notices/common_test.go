@@ -0,0 +1,103 @@
+package notices
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/transfer360/go-transfer360/vrm"
+)
+
+func TestValidateCommon(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		change  func(c *Common)
+		wantErr bool
+		err     error
+	}{
+		{"valid", func(c *Common) {}, false, nil},
+		{"not rfc3339", func(c *Common) { c.ContraventionDateTime = "2025-06-01 10:00:00" }, true, nil},
+		{"future", func(c *Common) { c.ContraventionDateTime = time.Now().Add(time.Hour).Format(time.RFC3339) }, true, nil},
+		{"foreign plate as gb", func(c *Common) { c.VRM = "AB-123-CD" }, true, vrm.ErrForeignRegistration},
+		{"foreign plate with country", func(c *Common) { c.VRM, c.VRMCountry = "AB-123-CD", "fr" }, false, nil},
+		{"invalid country", func(c *Common) { c.VRMCountry = "Atlantis" }, true, vrm.ErrInvalidCountryCode},
+		{"missing notice number", func(c *Common) { c.NoticeNumber = "" }, true, nil},
+		{"missing location", func(c *Common) { c.Location = "" }, true, nil},
+		{"no amount", func(c *Common) { c.Amount = 0 }, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validCommon()
+			tt.change(&c)
+			err := c.validateCommon()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got %v, want error %v", err, tt.wantErr)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("got %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateCommonNormalises(t *testing.T) {
+
+	c := validCommon()
+	c.VRM, c.VRMCountry = "ab-123-cd", "France"
+	if err := c.validateCommon(); err != nil {
+		t.Fatal(err)
+	}
+	if c.VRM != "AB123CD" || c.VRMCountry != "FR" {
+		t.Errorf("got %q %q", c.VRM, c.VRMCountry)
+	}
+}
+
+func TestRoadUserCharge(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		scheme     string
+		chargeDate string
+		valid      bool
+	}{
+		{"dart", "DART", "2025-06-01", true},
+		{"scheme is normalised", " ulez ", "2025-06-01", true},
+		{"unknown scheme", "M6TOLL", "2025-06-01", false},
+		{"missing scheme", "", "2025-06-01", false},
+		{"bad charge date", "CAZ", "01/06/2025", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &RoadUserCharge{Common: validCommon(), Scheme: tt.scheme, ChargeDate: tt.chargeDate}
+			if err := n.Validate(); (err == nil) != tt.valid {
+				t.Errorf("got %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestByelaw(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		byelaw    string
+		landowner string
+		valid     bool
+	}{
+		{"valid", "Railway Byelaws 2005 s14", "Network Rail", true},
+		{"missing byelaw", "", "Network Rail", false},
+		{"missing landowner", "Railway Byelaws 2005 s14", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &Byelaw{Common: validCommon(), Byelaw: tt.byelaw, Landowner: tt.landowner}
+			if err := n.Validate(); (err == nil) != tt.valid {
+				t.Errorf("got %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package notices
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// contraventionCode - Traffic Management Act 2004 contravention code, two digits with an optional suffix letter
+var contraventionCode = regexp.MustCompile(`^([0-9]{2})([A-Za-z]?)$`)
+
+// busLaneCodes - Traffic Management Act 2004 contravention codes for bus lane and bus route offences
+var busLaneCodes = map[int]bool{
+	33: true, // using a route restricted to certain vehicles, e.g. a bus gate
+	34: true, // being in a bus lane
+}
+
+// movingTrafficCodes - Traffic Management Act 2004 contravention codes for moving traffic offences, every other code
+// is a parking contravention
+var movingTrafficCodes = map[int]bool{
+	31: true, // entering and stopping in a box junction when prohibited
+	32: true, // failing to drive in the direction shown by the arrow on a blue sign
+	50: true, // performing a prohibited turn
+	51: true, // failing to comply with a no entry sign
+	52: true, // failing to comply with a prohibition on certain types of vehicle
+	53: true, // failing to comply with a restriction on vehicles entering a pedestrian zone
+	54: true, // failing to comply with a restriction on vehicles entering and waiting in a pedestrian zone
+	58: true, // using a vehicle on a restricted street during prescribed hours without a valid permit
+	59: true, // using a vehicle on a restricted street during prescribed hours in breach of permit conditions
+}
+
+func parseContraventionCode(code string) (int, error) {
+	m := contraventionCode.FindStringSubmatch(code)
+	if m == nil {
+		return 0, fmt.Errorf("invalid contravention code [%s]", code)
+	}
+	n, _ := strconv.Atoi(m[1])
+	if n == 0 {
+		return 0, fmt.Errorf("invalid contravention code [%s]", code)
+	}
+	return n, nil
+}
+
+// CouncilPenaltyCharge - a civil parking Penalty Charge Notice issued by a council under the Traffic Management Act 2004
+type CouncilPenaltyCharge struct {
+	Common
+	ContraventionCode string `json:"contravention_code" validate:"required"`
+	// CivilEnforcementOfficer - officer number for notices served by hand, empty for postal (camera) notices
+	CivilEnforcementOfficer string `json:"civil_enforcement_officer,omitempty"`
+}
+
+func (n *CouncilPenaltyCharge) Type() Type       { return TypeCouncilPenaltyCharge }
+func (n *CouncilPenaltyCharge) Endpoint() string { return "/notice/penalty_charge" }
+
+func (n *CouncilPenaltyCharge) Validate() error {
+	if err := n.validateCommon(); err != nil {
+		return err
+	}
+	code, err := parseContraventionCode(n.ContraventionCode)
+	if err != nil {
+		return err
+	}
+	if busLaneCodes[code] || movingTrafficCodes[code] {
+		return fmt.Errorf("contravention code [%s] is a bus lane or moving traffic code, use the matching notice type", n.ContraventionCode)
+	}
+	validate := validator.New()
+	return validate.Struct(n)
+}
+
+// BusLane - a camera enforced bus lane Penalty Charge Notice
+type BusLane struct {
+	Common
+	ContraventionCode string `json:"contravention_code" validate:"required"`
+	CameraID          string `json:"camera_id" validate:"required"`
+}
+
+func (n *BusLane) Type() Type       { return TypeBusLane }
+func (n *BusLane) Endpoint() string { return "/notice/bus_lane" }
+
+func (n *BusLane) Validate() error {
+	if err := n.validateCommon(); err != nil {
+		return err
+	}
+	code, err := parseContraventionCode(n.ContraventionCode)
+	if err != nil {
+		return err
+	}
+	if !busLaneCodes[code] {
+		return fmt.Errorf("contravention code [%s] is not a bus lane code", n.ContraventionCode)
+	}
+	validate := validator.New()
+	return validate.Struct(n)
+}
+
+// MovingTraffic - a camera enforced moving traffic Penalty Charge Notice, e.g. a banned turn or no entry
+type MovingTraffic struct {
+	Common
+	ContraventionCode string `json:"contravention_code" validate:"required"`
+	CameraID          string `json:"camera_id" validate:"required"`
+}
+
+func (n *MovingTraffic) Type() Type       { return TypeMovingTraffic }
+func (n *MovingTraffic) Endpoint() string { return "/notice/moving_traffic" }
+
+func (n *MovingTraffic) Validate() error {
+	if err := n.validateCommon(); err != nil {
+		return err
+	}
+	code, err := parseContraventionCode(n.ContraventionCode)
+	if err != nil {
+		return err
+	}
+	if !movingTrafficCodes[code] {
+		return fmt.Errorf("contravention code [%s] is not a moving traffic code", n.ContraventionCode)
+	}
+	validate := validator.New()
+	return validate.Struct(n)
+}
+
+func init() {
+	Register(TypeCouncilPenaltyCharge, func() Notice { return &CouncilPenaltyCharge{} })
+	Register(TypeBusLane, func() Notice { return &BusLane{} })
+	Register(TypeMovingTraffic, func() Notice { return &MovingTraffic{} })
+}
@@ -0,0 +1,84 @@
+package notices
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validCommon() Common {
+	return Common{
+		SearchReference:       "SREF1",
+		NoticeNumber:          "LB12345678",
+		VRM:                   "AB12 CDE",
+		ContraventionDateTime: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		Location:              "High Street",
+		IssuedBy:              "Leeds City Council",
+		Amount:                7000,
+	}
+}
+
+func TestContraventionCodes(t *testing.T) {
+
+	tests := []struct {
+		code          string
+		council       bool
+		busLane       bool
+		movingTraffic bool
+	}{
+		{"01", true, false, false},
+		{"12", true, false, false},  // resident or shared use bay
+		{"12s", true, false, false}, // suffixed
+		{"30", true, false, false},
+		{"61", true, false, false}, // footway parking
+		{"62", true, false, false},
+		{"31", false, false, true}, // box junction
+		{"31J", false, false, true},
+		{"32", false, false, true}, // blue arrow sign
+		{"33", false, true, false},
+		{"34", false, true, false}, // bus lane
+		{"34J", false, true, false},
+		{"50", false, false, true},
+		{"51", false, false, true},
+		{"52", false, false, true},
+		{"53", false, false, true},
+		{"54", false, false, true},
+		{"55", true, false, false}, // commercial vehicle overnight waiting ban
+		{"58", false, false, true},
+		{"59", false, false, true},
+		{"00", false, false, false},
+		{"1", false, false, false},
+		{"123", false, false, false},
+		{"12AB", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+
+			council := &CouncilPenaltyCharge{Common: validCommon(), ContraventionCode: tt.code}
+			if err := council.Validate(); (err == nil) != tt.council {
+				t.Errorf("CouncilPenaltyCharge got %v, want valid %v", err, tt.council)
+			}
+
+			busLane := &BusLane{Common: validCommon(), ContraventionCode: tt.code, CameraID: "CAM1"}
+			if err := busLane.Validate(); (err == nil) != tt.busLane {
+				t.Errorf("BusLane got %v, want valid %v", err, tt.busLane)
+			}
+
+			moving := &MovingTraffic{Common: validCommon(), ContraventionCode: tt.code, CameraID: "CAM1"}
+			if err := moving.Validate(); (err == nil) != tt.movingTraffic {
+				t.Errorf("MovingTraffic got %v, want valid %v", err, tt.movingTraffic)
+			}
+		})
+	}
+}
+
+func TestCameraRequired(t *testing.T) {
+
+	if err := (&BusLane{Common: validCommon(), ContraventionCode: "34"}).Validate(); err == nil || !strings.Contains(err.Error(), "CameraID") {
+		t.Errorf("BusLane without a camera got %v", err)
+	}
+	if err := (&MovingTraffic{Common: validCommon(), ContraventionCode: "31"}).Validate(); err == nil || !strings.Contains(err.Error(), "CameraID") {
+		t.Errorf("MovingTraffic without a camera got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+// Package notices sends every kind of enforcement notice through one interface, each notice type carries its own
+// validation rules and the api endpoint it is sent to
+package notices
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BaseURL - the Transfer360 api server notices are sent to
+const BaseURL = "https://api.transfer360.io"
+
+var ErrNoticeAlreadyExists = errors.New("notice already exists")
+var ErrIssuerNotSetup = errors.New("issuer is not setup")
+var ErrUnknownNoticeType = errors.New("unknown notice type")
+
+// Type - the kind of enforcement notice, stored as search.NoticeInformation.NoticeType
+type Type int
+
+const (
+	TypeUnknown Type = iota
+	TypeParkingCharge
+	TypeCouncilPenaltyCharge
+	TypeBusLane
+	TypeMovingTraffic
+	TypeRoadUserCharge
+	TypeByelaw
+)
+
+var typeNames = map[Type]string{
+	TypeUnknown:              "unknown",
+	TypeParkingCharge:        "parking_charge",
+	TypeCouncilPenaltyCharge: "penalty_charge",
+	TypeBusLane:              "bus_lane",
+	TypeMovingTraffic:        "moving_traffic",
+	TypeRoadUserCharge:       "road_user_charge",
+	TypeByelaw:               "byelaw",
+}
+
+func (t Type) String() string {
+	if n, ok := typeNames[t]; ok {
+		return n
+	}
+	return fmt.Sprintf("type(%d)", int(t))
+}
+
+// Notice - an enforcement notice which can be sent to the api server
+type Notice interface {
+	// Type returns the kind of notice
+	Type() Type
+	// Endpoint returns the api path the notice is posted to, e.g. "/notice/bus_lane"
+	Endpoint() string
+	// Validate checks the notice before sending, it may normalise fields
+	Validate() error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[Type]func() Notice{}
+)
+
+// Register adds a constructor for a notice type so New and Decode can create it, notice types outside this package
+// register themselves when their package is imported
+func Register(t Type, newNotice func() Notice) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[t] = newNotice
+}
+
+// New returns an empty notice of the given type
+func New(t Type) (Notice, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	newNotice, ok := registry[t]
+	if !ok {
+		return nil, fmt.Errorf("%w [%s]", ErrUnknownNoticeType, t)
+	}
+	return newNotice(), nil
+}
+
+// Decode creates a notice of the given type from JSON
+func Decode(t Type, data []byte) (Notice, error) {
+
+	n, err := New(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, n); err != nil {
+		return nil, fmt.Errorf("decoding %s notice: %w", t, err)
+	}
+
+	return n, nil
+}
+
+// Send validates the notice and posts it to its endpoint on the Transfer360 api server
+func Send(ctx context.Context, n Notice, apiKey string) error {
+	return DefaultSender.Send(ctx, n, apiKey)
+}
@@ -0,0 +1,64 @@
+package notices
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+
+	tests := []struct {
+		t        Type
+		endpoint string
+	}{
+		{TypeCouncilPenaltyCharge, "/notice/penalty_charge"},
+		{TypeBusLane, "/notice/bus_lane"},
+		{TypeMovingTraffic, "/notice/moving_traffic"},
+		{TypeRoadUserCharge, "/notice/road_user_charge"},
+		{TypeByelaw, "/notice/byelaw"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.t.String(), func(t *testing.T) {
+			n, err := New(tt.t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n.Type() != tt.t || n.Endpoint() != tt.endpoint {
+				t.Errorf("got %s %s", n.Type(), n.Endpoint())
+			}
+		})
+	}
+
+	if _, err := New(TypeUnknown); !errors.Is(err, ErrUnknownNoticeType) {
+		t.Errorf("got %v, want %v", err, ErrUnknownNoticeType)
+	}
+	if _, err := New(Type(99)); !errors.Is(err, ErrUnknownNoticeType) {
+		t.Errorf("got %v, want %v", err, ErrUnknownNoticeType)
+	}
+}
+
+func TestDecode(t *testing.T) {
+
+	n, err := Decode(TypeBusLane, []byte(`{"sref":"SREF1","contravention_code":"34","camera_id":"CAM1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bl, ok := n.(*BusLane)
+	if !ok || bl.SearchReference != "SREF1" || bl.ContraventionCode != "34" || bl.CameraID != "CAM1" {
+		t.Errorf("got %#v", n)
+	}
+
+	if _, err = Decode(TypeBusLane, []byte(`{`)); err == nil {
+		t.Error("expected a decoding error")
+	}
+	if _, err = Decode(Type(99), []byte(`{}`)); !errors.Is(err, ErrUnknownNoticeType) {
+		t.Errorf("got %v, want %v", err, ErrUnknownNoticeType)
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	if TypeMovingTraffic.String() != "moving_traffic" || Type(99).String() != "type(99)" {
+		t.Errorf("got %s %s", TypeMovingTraffic, Type(99))
+	}
+}
@@ -0,0 +1,50 @@
+package notices
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/go-playground/validator/v10"
+)
+
+// RoadUserChargeSchemes - the road user charging and toll schemes a RoadUserCharge notice can be for
+var RoadUserChargeSchemes = map[string]string{
+	"DART":       "Dartford Crossing",
+	"CONGESTION": "London Congestion Charge",
+	"ULEZ":       "Ultra Low Emission Zone",
+	"CAZ":        "Clean Air Zone",
+	"LEZ":        "Low Emission Zone",
+	"MERSEY":     "Mersey Gateway",
+	"TOLL":       "Toll road or bridge",
+}
+
+// RoadUserCharge - a penalty for an unpaid road user charge, clean air zone or toll
+type RoadUserCharge struct {
+	Common
+	Scheme string `json:"scheme" validate:"required"`
+	// ChargeDate - the day the unpaid charge was due for, YYYY-MM-DD
+	ChargeDate string `json:"charge_date" validate:"required"`
+}
+
+func (n *RoadUserCharge) Type() Type       { return TypeRoadUserCharge }
+func (n *RoadUserCharge) Endpoint() string { return "/notice/road_user_charge" }
+
+func (n *RoadUserCharge) Validate() error {
+	if err := n.validateCommon(); err != nil {
+		return err
+	}
+	n.Scheme = strings.ToUpper(strings.TrimSpace(n.Scheme))
+	if _, ok := RoadUserChargeSchemes[n.Scheme]; !ok {
+		return fmt.Errorf("unknown road user charge scheme [%s]", n.Scheme)
+	}
+	if !govalidator.IsTime(n.ChargeDate, "2006-01-02") {
+		return fmt.Errorf("invalid ChargeDate format, should be YYYY-MM-DD - please see documentation")
+	}
+	validate := validator.New()
+	return validate.Struct(n)
+}
+
+func init() {
+	Register(TypeRoadUserCharge, func() Notice { return &RoadUserCharge{} })
+}
@@ -0,0 +1,216 @@
+package notices
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/evidence"
+	"github.com/transfer360/go-transfer360/fingerprint"
+)
+
+// Sender - posts notices to an api server, every notice type is sent through one so they share the same request
+// handling and error mapping
+type Sender struct {
+	// BaseURL - the api server, e.g. BaseURL or the URL of a fakeserver.Server in tests
+	BaseURL    string
+	HTTPClient *http.Client
+	// Duplicates - optional, when set notices which can be fingerprinted are checked against those already sent and
+	// recorded once the api server has them
+	Duplicates *fingerprint.Detector
+}
+
+var ErrLikelyDuplicate = fingerprint.ErrLikelyDuplicate
+var ErrEvidenceNotUploaded = errors.New("notice was sent but its evidence was not uploaded, retry with UploadEvidence")
+
+// Evidenced - notices with evidence files, uploaded once the api server has accepted the notice
+type Evidenced interface {
+	EvidenceFiles() (sref string, attachments []evidence.Attachment)
+}
+
+// Fingerprinter - notices which can be checked for duplicates
+type Fingerprinter interface {
+	Fingerprint() (fingerprint.Fingerprint, error)
+}
+
+// Warner - notices which collect warnings that do not stop them being sent
+type Warner interface {
+	Warn(warning string)
+}
+
+// NewSender returns a Sender posting to the given api server, with a 20 second timeout outside DEVELOPMENT
+func NewSender(baseURL string) *Sender {
+
+	client := &http.Client{}
+	if len(os.Getenv("DEVELOPMENT")) == 0 {
+		client.Timeout = time.Second * 20
+	}
+
+	return &Sender{BaseURL: baseURL, HTTPClient: client}
+}
+
+// DefaultSender - sends to the Transfer360 api server, used by Send
+var DefaultSender = NewSender(BaseURL)
+
+// Send validates the notice and posts it to its endpoint
+func (s *Sender) Send(ctx context.Context, n Notice, apiKey string) error {
+
+	if len(apiKey) == 0 {
+		return fmt.Errorf("missing API Key")
+	}
+
+	if err := n.Validate(); err != nil {
+		return fmt.Errorf("go-transfer360 %s notice invalid: %w", n.Type(), err)
+	}
+
+	noticeData, err := json.Marshal(n)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	if err = s.checkDuplicates(ctx, n); err != nil {
+		return err
+	}
+
+	log.Debugln(string(noticeData))
+
+	err = s.Post(ctx, n.Endpoint(), "application/json", bytes.NewBuffer(noticeData), apiKey)
+	if err == nil || errors.Is(err, ErrNoticeAlreadyExists) {
+		s.recordFingerprint(ctx, n)
+	}
+	if err != nil {
+		return err
+	}
+
+	if e, ok := n.(Evidenced); ok {
+		if err = s.UploadEvidence(ctx, e, apiKey); err != nil {
+			return fmt.Errorf("%w: %w", ErrEvidenceNotUploaded, err)
+		}
+	}
+
+	return nil
+}
+
+// UploadEvidence posts the notice's evidence files to the evidence endpoint, the notice must already have been sent
+func (s *Sender) UploadEvidence(ctx context.Context, n Evidenced, apiKey string) error {
+
+	sref, attachments := n.EvidenceFiles()
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	contentType, body, err := evidence.Multipart(sref, attachments)
+	if err != nil {
+		return err
+	}
+
+	if err = s.Post(ctx, evidence.Endpoint, contentType, body, apiKey); err != nil {
+		log.Warnf("%s | evidence %v", sref, err)
+		return fmt.Errorf("%w: %w", evidence.ErrUpload, err)
+	}
+
+	return nil
+}
+
+// checkDuplicates returns ErrLikelyDuplicate when the notice matches one already sent and warns about overlapping
+// stays, a failure to read the store is logged and does not stop the notice being sent
+func (s *Sender) checkDuplicates(ctx context.Context, n Notice) error {
+
+	fp, ok := n.(Fingerprinter)
+	if s.Duplicates == nil || !ok {
+		return nil
+	}
+
+	f, err := fp.Fingerprint()
+	if err != nil {
+		return err
+	}
+
+	matches, err := s.Duplicates.Check(ctx, f)
+	if err != nil {
+		log.Errorf("Sender.checkDuplicates:[%s]:%v", f.SearchReference, err)
+		return nil
+	}
+
+	for _, m := range matches {
+		if m.IsDuplicate() {
+			return fmt.Errorf("%w: %s", ErrLikelyDuplicate, m)
+		}
+		warning := fmt.Sprintf("stay overlaps notice %s already sent for the vehicle", m.Fingerprint.SearchReference)
+		log.Warnf("%s | %s", f.SearchReference, warning)
+		if w, ok := n.(Warner); ok {
+			w.Warn(warning)
+		}
+	}
+
+	return nil
+}
+
+func (s *Sender) recordFingerprint(ctx context.Context, n Notice) {
+
+	fp, ok := n.(Fingerprinter)
+	if s.Duplicates == nil || !ok {
+		return
+	}
+
+	f, err := fp.Fingerprint()
+	if err != nil {
+		log.Errorf("Sender.recordFingerprint:%v", err)
+		return
+	}
+
+	if err = s.Duplicates.Record(ctx, f); err != nil {
+		log.Errorf("Sender.recordFingerprint:[%s]:%v", f.SearchReference, err)
+	}
+}
+
+// Post sends a request body to an api path, mapping the api server's status codes to errors
+func (s *Sender) Post(ctx context.Context, path string, contentType string, body io.Reader, apiKey string) error {
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.BaseURL+path, body)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+	req.Header.Set("api_key", apiKey)
+	req.Header.Set("Content-Type", contentType)
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "Client.Timeout exceeded while awaiting headers") {
+			return context.DeadlineExceeded
+		}
+		log.Errorln(err)
+		return fmt.Errorf("sending go-transfer360 %s to api server %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	log.Debugf("response Body: %s", string(respBody))
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		return ErrNoticeAlreadyExists
+	case http.StatusTooEarly:
+		return ErrIssuerNotSetup
+	}
+
+	log.Warnf("Non-200: %d %s", resp.StatusCode, resp.Status)
+	return fmt.Errorf("error code returned from api server (%d)[%s]", resp.StatusCode, string(respBody))
+}
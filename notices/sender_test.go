@@ -0,0 +1,106 @@
+package notices
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/transfer360/go-transfer360/fingerprint"
+)
+
+func TestSenderStatus(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		status int
+		err    error
+	}{
+		{"accepted", http.StatusOK, nil},
+		{"already exists", http.StatusConflict, ErrNoticeAlreadyExists},
+		{"issuer not setup", http.StatusTooEarly, ErrIssuerNotSetup},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var got struct {
+				path   string
+				apiKey string
+				body   map[string]interface{}
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got.path, got.apiKey = r.URL.Path, r.Header.Get("api_key")
+				_ = json.NewDecoder(r.Body).Decode(&got.body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			n := &BusLane{Common: validCommon(), ContraventionCode: "34", CameraID: "CAM1"}
+			err := NewSender(server.URL).Send(context.Background(), n, "KEY1")
+			if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+			if got.path != "/notice/bus_lane" || got.apiKey != "KEY1" || got.body["vrm"] != "AB12CDE" {
+				t.Errorf("unexpected request %+v", got)
+			}
+		})
+	}
+}
+
+func TestSenderOtherStatus(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad notice", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := NewSender(server.URL).Send(context.Background(), &BusLane{Common: validCommon(), ContraventionCode: "34", CameraID: "CAM1"}, "KEY1")
+	if err == nil || !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "bad notice") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestSenderChecksBeforePosting(t *testing.T) {
+
+	posted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted++
+	}))
+	defer server.Close()
+
+	s := NewSender(server.URL)
+	n := &BusLane{Common: validCommon(), ContraventionCode: "34", CameraID: "CAM1"}
+
+	if err := s.Send(context.Background(), n, ""); err == nil {
+		t.Error("expected a missing api key error")
+	}
+	if err := s.Send(context.Background(), &BusLane{Common: validCommon(), ContraventionCode: "31", CameraID: "CAM1"}, "KEY1"); err == nil {
+		t.Error("expected a validation error")
+	}
+	if posted != 0 {
+		t.Errorf("posted %d notices", posted)
+	}
+}
+
+func TestSenderDuplicates(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	s := NewSender(server.URL)
+	s.Duplicates = fingerprint.NewDetector(fingerprint.NewMemoryStore())
+
+	if err := s.Send(context.Background(), &BusLane{Common: validCommon(), ContraventionCode: "34", CameraID: "CAM1"}, "KEY1"); err != nil {
+		t.Fatal(err)
+	}
+
+	again := &BusLane{Common: validCommon(), ContraventionCode: "34", CameraID: "CAM1"}
+	again.SearchReference = "SREF2"
+	if err := s.Send(context.Background(), again, "KEY1"); !errors.Is(err, ErrLikelyDuplicate) {
+		t.Errorf("got %v, want %v", err, ErrLikelyDuplicate)
+	}
+}
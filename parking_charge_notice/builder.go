@@ -0,0 +1,339 @@
+package parking_charge_notice
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/transfer360/go-transfer360/charge"
+	"github.com/transfer360/go-transfer360/evidence"
+	"github.com/transfer360/go-transfer360/jurisdiction"
+	"github.com/transfer360/go-transfer360/pofa"
+	"github.com/transfer360/go-transfer360/postcode"
+	"github.com/transfer360/go-transfer360/search"
+	"github.com/transfer360/go-transfer360/vrm"
+)
+
+var ErrInvalidPeriod = errors.New("period end is before its start")
+var ErrFutureDateTime = errors.New("date time is in the future")
+
+// Builder - builds an Information from typed values, checking each value as it is given and writing it to the
+// notice data sent to the api server. The first error stops further values being applied and is returned by Build.
+//
+//	notice, err := parking_charge_notice.NewBuilder().
+//		FromSearch(result).
+//		NoticeNumber("PCN0012345").
+//		ContraventionAt(contravention).
+//		ANPR(entry, exit).
+//		Site("Station Road Car Park, Leeds", "LS1 4DY").
+//		Charge(10000, 6000, 14).
+//		IssuedOn(time.Now()).
+//		Build()
+type Builder struct {
+	notice Information
+	err    error
+}
+
+// NewBuilder returns an empty Builder
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+func (b *Builder) fail(field string, err error) *Builder {
+	if b.err == nil {
+		b.err = fmt.Errorf("%s: %w", field, err)
+	}
+	return b
+}
+
+// Err returns the first error found so far
+func (b *Builder) Err() error {
+	return b.err
+}
+
+// FromSearch fills in the search reference, registration and contravention date of a search
+func (b *Builder) FromSearch(r search.Result) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	b.SearchReference(r.Sref)
+	if len(r.VRM) > 0 {
+		b.VRM(r.VRM, r.VRMCountry)
+	}
+	if len(r.ContraventionDate) > 0 {
+		t, err := time.Parse(time.RFC3339, r.ContraventionDate)
+		if err != nil {
+			return b.fail("contravention date", err)
+		}
+		b.ContraventionAt(t)
+	}
+
+	return b
+}
+
+// SearchReference sets the sref returned by the search
+func (b *Builder) SearchReference(sref string) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	sref = strings.TrimSpace(sref)
+	if len(sref) == 0 {
+		return b.fail("search reference", errors.New("required"))
+	}
+	b.notice.SearchReference = sref
+
+	return b
+}
+
+// NoticeNumber sets the issuer's number for the parking charge notice
+func (b *Builder) NoticeNumber(number string) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	number = strings.TrimSpace(number)
+	if len(number) == 0 {
+		return b.fail("notice number", errors.New("required"))
+	}
+	b.notice.NoticeNumber = number
+
+	return b
+}
+
+// VRM sets the vehicle registration with spaces and separators removed, country is the ISO 3166-1 country of the
+// registration or empty for GB. A registration which does not match a known format for its country is kept and
+// raised as a warning by Validate.
+func (b *Builder) VRM(registration string, country string) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	cc, err := vrm.NormaliseCountryCode(country)
+	if err != nil {
+		return b.fail("vrm country", err)
+	}
+
+	reg := vrm.Clean(registration)
+	if len(reg) == 0 {
+		return b.fail("vrm", vrm.ErrInvalidRegistration)
+	}
+
+	b.notice.VehicleRegistration = reg
+	if len(country) > 0 {
+		b.notice.VRMCountry = cc
+	}
+
+	return b
+}
+
+// ContraventionAt sets the date and time of the contravention
+func (b *Builder) ContraventionAt(t time.Time) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	if t.IsZero() {
+		return b.fail("contravention date time", errors.New("required"))
+	}
+	if t.After(time.Now()) {
+		return b.fail("contravention date time", ErrFutureDateTime)
+	}
+	b.notice.ContraventionDateTime = t.Format(time.RFC3339)
+
+	return b
+}
+
+// ANPR sets the entry and exit times captured by ANPR cameras
+func (b *Builder) ANPR(entry time.Time, exit time.Time) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	if err := checkPeriod(entry, exit); err != nil {
+		return b.fail("entry exit", err)
+	}
+	b.notice.EntryExit.Entry = entry.Format(time.RFC3339)
+	b.notice.EntryExit.Exit = exit.Format(time.RFC3339)
+
+	return b
+}
+
+// Observed sets the period a warden observed the vehicle
+func (b *Builder) Observed(from time.Time, to time.Time) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	if err := checkPeriod(from, to); err != nil {
+		return b.fail("observation", err)
+	}
+	b.notice.Observation.From = from.Format(time.RFC3339)
+	b.notice.Observation.To = to.Format(time.RFC3339)
+
+	return b
+}
+
+func checkPeriod(start time.Time, end time.Time) error {
+	if start.IsZero() || end.IsZero() {
+		return errors.New("start and end are required")
+	}
+	if end.Before(start) {
+		return ErrInvalidPeriod
+	}
+	if end.After(time.Now()) {
+		return ErrFutureDateTime
+	}
+	return nil
+}
+
+// Site sets the relevant land and its postcode, the postcode is used to detect the jurisdiction
+func (b *Builder) Site(relevantLand string, sitePostcode string) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	relevantLand = strings.TrimSpace(relevantLand)
+	if len(relevantLand) == 0 {
+		return b.fail("site", errors.New("relevant land is required"))
+	}
+	b.notice.Location.SiteName = relevantLand
+
+	if len(sitePostcode) > 0 {
+		pc, err := postcode.Normalise(sitePostcode)
+		if err != nil {
+			return b.fail("site postcode", err)
+		}
+		b.notice.Location.PostCode = pc
+	}
+
+	return b
+}
+
+// Jurisdiction sets the jurisdiction of the site, e.g. "scotland", when it cannot be detected from the postcode
+func (b *Builder) Jurisdiction(name string) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	j, err := jurisdiction.Parse(name)
+	if err != nil {
+		return b.fail("jurisdiction", err)
+	}
+	b.notice.Jurisdiction = string(j)
+
+	return b
+}
+
+// Charge sets the parking charge and discount in pence, discount may be 0 if none is offered
+func (b *Builder) Charge(fullAmount int, discountAmount int, discountDays int) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	b.notice.Charge.FullAmount = fullAmount
+	b.notice.Charge.DiscountAmount = discountAmount
+	b.notice.Charge.DiscountDays = discountDays
+
+	return b.checkCharge()
+}
+
+// IssuedOn sets the date the notice is issued, from which the discount period and AmountPayable are counted
+func (b *Builder) IssuedOn(t time.Time) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	if t.IsZero() {
+		return b.fail("issue date", errors.New("required"))
+	}
+	b.notice.IssueDate = t.Format("2006-01-02")
+
+	return b
+}
+
+// Caps sets the caps the charge is checked against instead of charge.DefaultCaps
+func (b *Builder) Caps(caps charge.Caps) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	b.notice.ChargeCaps = &caps
+
+	return b.checkCharge()
+}
+
+func (b *Builder) checkCharge() *Builder {
+
+	schedule := b.notice.ChargeSchedule()
+	if schedule == nil {
+		return b
+	}
+
+	if err := schedule.Validate(b.notice.chargeCaps()); err != nil {
+		return b.fail("charge", err)
+	}
+
+	return b
+}
+
+// Pofa sets how the driver was first notified, driverGiven is when a windscreen notice was given and may be zero
+func (b *Builder) Pofa(noticeType pofa.NoticeType, driverGiven time.Time) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	if noticeType != pofa.ANPR && noticeType != pofa.Windscreen {
+		return b.fail("pofa notice type", pofa.ErrUnknownNoticeType)
+	}
+	b.notice.PofaNoticeType = noticeType
+	b.notice.NoticeToDriverGiven = driverGiven
+
+	return b
+}
+
+// Attach adds an evidence file, see Information.Attach
+func (b *Builder) Attach(filename string, r io.Reader) *Builder {
+
+	if b.err != nil {
+		return b
+	}
+
+	if err := b.notice.Attach(filename, r); err != nil {
+		return b.fail("evidence", err)
+	}
+
+	return b
+}
+
+// Build validates the notice and returns it ready to Send
+func (b *Builder) Build() (*Information, error) {
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	notice := b.notice
+	notice.Evidence = append([]evidence.Attachment{}, b.notice.Evidence...)
+	if err := notice.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &notice, nil
+}
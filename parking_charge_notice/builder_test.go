@@ -0,0 +1,112 @@
+package parking_charge_notice
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/transfer360/go-transfer360/charge"
+	"github.com/transfer360/go-transfer360/search"
+)
+
+func TestBuilderWritesNoticeData(t *testing.T) {
+
+	contravention := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+
+	notice, err := NewBuilder().
+		FromSearch(search.Result{Sref: "SREF1", VRM: "ab12 cde", ContraventionDate: contravention.Format(time.RFC3339)}).
+		NoticeNumber("PCN0012345").
+		ANPR(contravention.Add(-time.Hour), contravention).
+		Site("Station Road Car Park", "ls1 4dy").
+		Charge(10000, 6000, 14).
+		IssuedOn(contravention).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	data, err := json.Marshal(notice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`"SREF1"`,
+		`"PCN0012345"`,
+		`"AB12CDE"`,
+		`"` + contravention.Format(time.RFC3339) + `"`,
+		`"Station Road Car Park"`,
+		`"LS1 4DY"`,
+		`10000`,
+		`6000`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("notice json %s does not contain %s", data, want)
+		}
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+
+	now := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		builder *Builder
+		wantErr error
+	}{
+		{
+			"exit before entry",
+			NewBuilder().SearchReference("S").ANPR(now, now.Add(-time.Minute)),
+			ErrInvalidPeriod,
+		},
+		{
+			"future contravention",
+			NewBuilder().SearchReference("S").ContraventionAt(time.Now().Add(time.Hour)),
+			ErrFutureDateTime,
+		},
+		{
+			"charge over cap",
+			NewBuilder().SearchReference("S").Charge(20000, 0, 0),
+			charge.ErrExceedsCap,
+		},
+		{
+			"first error is kept",
+			NewBuilder().ANPR(now, now.Add(-time.Minute)).Charge(20000, 0, 0),
+			ErrInvalidPeriod,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuilderKeepsUnrecognisedRegistration(t *testing.T) {
+
+	contravention := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+
+	notice, err := NewBuilder().
+		SearchReference("SREF1").
+		VRM("ab-123-cd", "").
+		ContraventionAt(contravention).
+		Charge(10000, 6000, 14).
+		IssuedOn(contravention).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if notice.VehicleRegistration != "AB123CD" || len(notice.VRMCountry) > 0 {
+		t.Errorf("got registration %q country %q", notice.VehicleRegistration, notice.VRMCountry)
+	}
+	if len(notice.Warnings) != 1 || !strings.Contains(notice.Warnings[0], "AB123CD") {
+		t.Errorf("expected a registration warning, got %v", notice.Warnings)
+	}
+}
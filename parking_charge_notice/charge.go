@@ -0,0 +1,66 @@
+package parking_charge_notice
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/transfer360/go-transfer360/charge"
+)
+
+// ChargeSchedule returns the charge amounts in the notice data, nil if the notice has none
+func (notice *Information) ChargeSchedule() *charge.Schedule {
+
+	if notice.Charge.FullAmount == 0 && notice.Charge.DiscountAmount == 0 {
+		return nil
+	}
+
+	return &charge.Schedule{
+		FullAmount:     notice.Charge.FullAmount,
+		DiscountAmount: notice.Charge.DiscountAmount,
+		DiscountDays:   notice.Charge.DiscountDays,
+	}
+}
+
+func (notice *Information) chargeCaps() charge.Caps {
+	if notice.ChargeCaps != nil {
+		return *notice.ChargeCaps
+	}
+	return charge.DefaultCaps
+}
+
+// issueDate parses the IssueDate of the notice data, either RFC3339 or a plain date
+func (notice *Information) issueDate() (time.Time, error) {
+
+	if len(notice.IssueDate) == 0 {
+		return time.Time{}, ErrNoIssueDate
+	}
+
+	if t, err := time.Parse(time.RFC3339, notice.IssueDate); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", notice.IssueDate, time.UTC); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid IssueDate format, should be RFC3339 or YYYY-MM-DD - please see documentation")
+}
+
+// AmountPayable ----------------------------------------------------------------------------------------------------
+// returns the amount due on the given date from the charge amounts and issue date in the notice data, escalations
+// are the issuer's late payment costs which are not part of the notice
+func (notice *Information) AmountPayable(on time.Time, escalations ...charge.Escalation) (charge.Payable, error) {
+
+	schedule := notice.ChargeSchedule()
+	if schedule == nil {
+		return charge.Payable{}, ErrNoChargeSchedule
+	}
+
+	issued, err := notice.issueDate()
+	if err != nil {
+		return charge.Payable{}, err
+	}
+
+	schedule.Escalations = escalations
+
+	return schedule.AmountPayable(issued, on), nil
+}
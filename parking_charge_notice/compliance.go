@@ -0,0 +1,45 @@
+package parking_charge_notice
+
+import (
+	"github.com/transfer360/go-transfer360/compliance"
+	"github.com/transfer360/go-transfer360/jurisdiction"
+)
+
+// CheckCompliance ----------------------------------------------------------------------------------------------------
+// checks the notice and the issuer's creditor details against a compliance rule set, compliance.Latest if version
+// is empty. A failed rule does not stop the notice being sent.
+func (notice *Information) CheckCompliance(version string, issuer compliance.IssuerDetails) (compliance.Report, error) {
+
+	if len(version) == 0 {
+		version = compliance.Latest
+	}
+
+	rs, err := compliance.Get(version)
+	if err != nil {
+		return compliance.Report{}, err
+	}
+
+	j, err := jurisdiction.Detect(notice.Jurisdiction, notice.Location.PostCode)
+	if err != nil {
+		return compliance.Report{}, err
+	}
+
+	facts := compliance.Facts{
+		SearchReference:         notice.SearchReference,
+		ContraventionDateTime:   notice.ContraventionDateTime,
+		RelevantLand:            notice.Location.SiteName,
+		Charge:                  notice.ChargeSchedule(),
+		ChargeCaps:              notice.chargeCaps(),
+		Jurisdiction:            j,
+		ReliesOnKeeperLiability: len(notice.PofaNoticeType) > 0,
+		Issuer:                  issuer,
+	}
+
+	if len(notice.EntryExit.Entry) > 0 && len(notice.EntryExit.Exit) > 0 {
+		facts.PeriodStart, facts.PeriodEnd = notice.EntryExit.Entry, notice.EntryExit.Exit
+	} else {
+		facts.PeriodStart, facts.PeriodEnd = notice.Observation.From, notice.Observation.To
+	}
+
+	return rs.Evaluate(facts), nil
+}
@@ -0,0 +1,41 @@
+package parking_charge_notice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/transfer360/go-transfer360/fingerprint"
+)
+
+var ErrNoRegistration = errors.New("notice has no VehicleRegistration")
+
+// Fingerprint returns what identifies the notice for duplicate detection, taken from the notice data, the stay is
+// from the entry and exit or observation times
+func (notice *Information) Fingerprint() (fingerprint.Fingerprint, error) {
+
+	if len(notice.VehicleRegistration) == 0 {
+		return fingerprint.Fingerprint{}, ErrNoRegistration
+	}
+
+	contravention, err := time.Parse(time.RFC3339, notice.ContraventionDateTime)
+	if err != nil {
+		return fingerprint.Fingerprint{}, fmt.Errorf("invalid ContraventionDatetime: %w", err)
+	}
+
+	start, end := notice.EntryExit.Entry, notice.EntryExit.Exit
+	if len(start) == 0 || len(end) == 0 {
+		start, end = notice.Observation.From, notice.Observation.To
+	}
+	// unparsable or missing times are zero, New falls back to the contravention time
+	startTime, _ := time.Parse(time.RFC3339, start)
+	endTime, _ := time.Parse(time.RFC3339, end)
+
+	site := strings.TrimSpace(notice.Location.PostCode + " " + notice.Location.SiteName)
+
+	f := fingerprint.New(notice.VehicleRegistration, site, notice.NoticeNumber, contravention, startTime, endTime)
+	f.SearchReference = notice.SearchReference
+
+	return f, nil
+}
@@ -0,0 +1,36 @@
+package parking_charge_notice
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFingerprintFromNoticeData(t *testing.T) {
+
+	notice := &Information{}
+	notice.SearchReference = "SREF1"
+	notice.NoticeNumber = "PCN0012345"
+	notice.VehicleRegistration = "ab12 cde"
+	notice.ContraventionDateTime = "2025-06-02T10:00:00Z"
+	notice.EntryExit.Entry = "2025-06-02T09:00:00Z"
+	notice.EntryExit.Exit = "2025-06-02T10:00:00Z"
+	notice.Location.SiteName = "Station Road Car Park"
+	notice.Location.PostCode = "LS1 4DY"
+
+	f, err := notice.Fingerprint()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if f.VRM != "AB12CDE" || f.NoticeNumber != "PCN0012345" || f.Site != "LS1 4DY STATION ROAD CAR PARK" || f.SearchReference != "SREF1" {
+		t.Errorf("unexpected fingerprint %+v", f)
+	}
+	if f.End.Sub(f.Start).Hours() != 1 {
+		t.Errorf("stay not taken from entry and exit %+v", f)
+	}
+
+	notice.VehicleRegistration = ""
+	if _, err = notice.Fingerprint(); !errors.Is(err, ErrNoRegistration) {
+		t.Errorf("got %v, want %v", err, ErrNoRegistration)
+	}
+}
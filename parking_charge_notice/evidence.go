@@ -0,0 +1,52 @@
+package parking_charge_notice
+
+import (
+	"context"
+	"io"
+
+	"github.com/transfer360/go-transfer360/evidence"
+	"github.com/transfer360/go-transfer360/notices"
+)
+
+// Attach adds an evidence file to the notice, it must be a JPEG, PNG or PDF within evidence.DefaultLimits
+func (notice *Information) Attach(filename string, r io.Reader) error {
+
+	a, err := evidence.New(filename, r, evidence.DefaultLimits)
+	if err != nil {
+		return err
+	}
+
+	return notice.addEvidence(a)
+}
+
+// AttachFile adds an evidence file from disk to the notice
+func (notice *Information) AttachFile(path string) error {
+
+	a, err := evidence.Open(path, evidence.DefaultLimits)
+	if err != nil {
+		return err
+	}
+
+	return notice.addEvidence(a)
+}
+
+func (notice *Information) addEvidence(a evidence.Attachment) error {
+
+	if err := evidence.CheckLimits(append(notice.Evidence[:len(notice.Evidence):len(notice.Evidence)], a), evidence.DefaultLimits); err != nil {
+		return err
+	}
+	notice.Evidence = append(notice.Evidence, a)
+
+	return nil
+}
+
+// EvidenceFiles returns the search reference and evidence files, uploaded by notices.Sender once the notice is sent
+func (notice *Information) EvidenceFiles() (string, []evidence.Attachment) {
+	return notice.SearchReference, notice.Evidence
+}
+
+// UploadEvidence uploads the evidence files of a notice already sent, to retry after Send returns
+// ErrEvidenceNotUploaded
+func (notice *Information) UploadEvidence(apiKey string) error {
+	return notices.DefaultSender.UploadEvidence(context.Background(), notice, apiKey)
+}
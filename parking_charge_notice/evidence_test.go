@@ -0,0 +1,48 @@
+package parking_charge_notice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/transfer360/go-transfer360/fakeserver"
+	"github.com/transfer360/go-transfer360/notices"
+	"github.com/transfer360/go-transfer360/search"
+)
+
+func TestSendWithEvidence(t *testing.T) {
+
+	srv := fakeserver.New()
+	defer srv.Close()
+	sender := notices.NewSender(srv.URL)
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+	contravention := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	notice, err := NewBuilder().
+		FromSearch(search.Result{Sref: "SREF1", VRM: "AB12CDE", ContraventionDate: contravention.Format(time.RFC3339)}).
+		Site("Station Road Car Park", "LS1 4DY").
+		Attach("exit.png", bytes.NewReader(png)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.FailEvidence(1, http.StatusBadGateway)
+
+	if err = notice.SendWith(context.Background(), sender, "key"); !errors.Is(err, ErrEvidenceNotUploaded) {
+		t.Fatalf("got %v, want %v", err, ErrEvidenceNotUploaded)
+	}
+
+	if err = sender.UploadEvidence(context.Background(), notice, "key"); err != nil {
+		t.Fatalf("retry: unexpected error %v", err)
+	}
+
+	uploaded := srv.Evidence("SREF1")
+	if len(uploaded) != 1 || uploaded[0].SHA256 != notice.Evidence[0].SHA256 {
+		t.Fatalf("unexpected evidence %+v", uploaded)
+	}
+}
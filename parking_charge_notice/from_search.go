@@ -0,0 +1,135 @@
+package parking_charge_notice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/transfer360/go-transfer360/charge"
+	"github.com/transfer360/go-transfer360/pofa"
+	"github.com/transfer360/go-transfer360/search"
+	"github.com/transfer360/go-transfer360/vrm"
+)
+
+var ErrNotHirerVehicle = errors.New("search result is not a hirer vehicle")
+var ErrSearchMismatch = errors.New("search result does not match the search request")
+
+// SiteDetails - the parts of a notice which do not come from the search
+type SiteDetails struct {
+	// the issuer's number for the parking charge notice
+	NoticeNumber string
+	RelevantLand string
+	SitePostcode string
+	// optional, see Builder.Jurisdiction
+	Jurisdiction string
+	// ANPR entry and exit or warden observation times, one pair is expected
+	Entry        time.Time
+	Exit         time.Time
+	ObservedFrom time.Time
+	ObservedTo   time.Time
+	// optional, checked against Caps or charge.DefaultCaps, escalations are not part of the notice and are ignored
+	Charge *charge.Schedule
+	Caps   *charge.Caps
+	// optional, see Builder.Pofa
+	PofaNoticeType      pofa.NoticeType
+	NoticeToDriverGiven time.Time
+}
+
+// FromSearchResult --------------------------------------------------------------------------------------------------
+// builds a notice for a hirer vehicle returned by SendEnquiry, checking the result is for the vehicle, contravention
+// and reference of the original request
+func FromSearchResult(req search.Request, res search.Result, site SiteDetails) (*Information, error) {
+
+	if !res.IsHirerVehicle {
+		return nil, fmt.Errorf("%w [%s]", ErrNotHirerVehicle, res.Sref)
+	}
+
+	if err := checkSearchMatches(req, res); err != nil {
+		return nil, err
+	}
+
+	b := NewBuilder().FromSearch(res)
+	if len(site.NoticeNumber) > 0 {
+		b.NoticeNumber(site.NoticeNumber)
+	}
+
+	if !site.Entry.IsZero() || !site.Exit.IsZero() {
+		b.ANPR(site.Entry, site.Exit)
+	}
+	if !site.ObservedFrom.IsZero() || !site.ObservedTo.IsZero() {
+		b.Observed(site.ObservedFrom, site.ObservedTo)
+	}
+
+	b.Site(site.RelevantLand, site.SitePostcode)
+	if len(site.Jurisdiction) > 0 {
+		b.Jurisdiction(site.Jurisdiction)
+	}
+
+	if site.Caps != nil {
+		b.Caps(*site.Caps)
+	}
+	if site.Charge != nil {
+		b.Charge(site.Charge.FullAmount, site.Charge.DiscountAmount, site.Charge.DiscountDays)
+	}
+
+	if len(site.PofaNoticeType) > 0 {
+		b.Pofa(site.PofaNoticeType, site.NoticeToDriverGiven)
+	}
+
+	notice, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	// the notice data is what the api server receives, check it still carries the vehicle and contravention searched
+	sent := search.Result{
+		VRM:               notice.VehicleRegistration,
+		VRMCountry:        notice.VRMCountry,
+		ContraventionDate: notice.ContraventionDateTime,
+	}
+	if err = checkSearchMatches(req, sent); err != nil {
+		return nil, err
+	}
+
+	return notice, nil
+}
+
+func checkSearchMatches(req search.Request, res search.Result) error {
+
+	country, err := vrm.NormaliseCountryCode(req.VRMCountry)
+	if err != nil {
+		return err
+	}
+	if len(res.VRMCountry) > 0 {
+		resCountry, err := vrm.NormaliseCountryCode(res.VRMCountry)
+		if err != nil {
+			return err
+		}
+		if resCountry != country {
+			return fmt.Errorf("%w: vrm country %s searched, %s returned", ErrSearchMismatch, country, resCountry)
+		}
+	}
+
+	if vrm.Clean(req.VRM) != vrm.Clean(res.VRM) {
+		return fmt.Errorf("%w: vrm %s searched, %s returned", ErrSearchMismatch, req.VRM, res.VRM)
+	}
+
+	requested, err := time.Parse(time.RFC3339, req.DateTime)
+	if err != nil {
+		return fmt.Errorf("invalid search request datetime: %w", err)
+	}
+	returned, err := time.Parse(time.RFC3339, res.ContraventionDate)
+	if err != nil {
+		return fmt.Errorf("invalid search result contravention date: %w", err)
+	}
+	if !requested.Equal(returned) {
+		return fmt.Errorf("%w: contravention %s searched, %s returned", ErrSearchMismatch, req.DateTime, res.ContraventionDate)
+	}
+
+	if len(res.Reference) > 0 && !strings.EqualFold(strings.TrimSpace(req.Reference), strings.TrimSpace(res.Reference)) {
+		return fmt.Errorf("%w: reference %s searched, %s returned", ErrSearchMismatch, req.Reference, res.Reference)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package parking_charge_notice
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/transfer360/go-transfer360/charge"
+	"github.com/transfer360/go-transfer360/search"
+)
+
+func TestFromSearchResult(t *testing.T) {
+
+	contravention := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+
+	req := search.Request{VRM: "AB12 CDE", DateTime: contravention.Format(time.RFC3339), Reference: "REF1"}
+	hirer := search.Result{
+		Sref:              "SREF1",
+		IsHirerVehicle:    true,
+		VRM:               "AB12CDE",
+		ContraventionDate: contravention.Format(time.RFC3339),
+		Reference:         "REF1",
+	}
+	site := SiteDetails{
+		NoticeNumber: "PCN0012345",
+		RelevantLand: "Station Road Car Park",
+		SitePostcode: "LS1 4DY",
+		Entry:        contravention.Add(-time.Hour),
+		Exit:         contravention,
+		Charge:       &charge.Schedule{FullAmount: 10000, DiscountAmount: 6000, DiscountDays: 14},
+	}
+
+	t.Run("notice json carries the search vrm and contravention", func(t *testing.T) {
+
+		notice, err := FromSearchResult(req, hirer, site)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		data, err := json.Marshal(notice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{`"AB12CDE"`, `"` + req.DateTime + `"`, `"SREF1"`, `"PCN0012345"`} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("notice json %s does not contain %s", data, want)
+			}
+		}
+	})
+
+	tests := []struct {
+		name    string
+		result  func(search.Result) search.Result
+		wantErr error
+	}{
+		{"not a hirer vehicle", func(r search.Result) search.Result { r.IsHirerVehicle = false; return r }, ErrNotHirerVehicle},
+		{"different vrm", func(r search.Result) search.Result { r.VRM = "AB12CDF"; return r }, ErrSearchMismatch},
+		{"different country", func(r search.Result) search.Result { r.VRMCountry = "IE"; return r }, ErrSearchMismatch},
+		{"different contravention", func(r search.Result) search.Result {
+			r.ContraventionDate = contravention.Add(time.Minute).Format(time.RFC3339)
+			return r
+		}, ErrSearchMismatch},
+		{"different reference", func(r search.Result) search.Result { r.Reference = "REF2"; return r }, ErrSearchMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromSearchResult(req, tt.result(hirer), site); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
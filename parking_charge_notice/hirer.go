@@ -5,11 +5,16 @@ import (
 	"context"
 	"errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/address"
+	"github.com/transfer360/go-transfer360/country"
+	"github.com/transfer360/go-transfer360/postcode"
 	"google.golang.org/api/iterator"
+	"strings"
 )
 
 type HirerInformation struct {
 	CompanyName  string `json:"company_name,omitempty" bigquery:"CompanyName"`
+	Title        string `json:"title,omitempty" bigquery:"Title"`
 	Name         string `json:"name,omitempty" bigquery:"Name"`
 	Surname      string `json:"surname,omitempty" bigquery:"Surname"`
 	AddressLine1 string `json:"address_line_1,omitempty" bigquery:"AddressLine1"`
@@ -22,6 +27,71 @@ type HirerInformation struct {
 
 var ERRHirerNotFound = errors.New("hirer not found")
 
+// CountryCode returns the ISO 3166-1 alpha-2 code for the hirer's country, an empty country is treated as the UK
+func (h HirerInformation) CountryCode() (string, error) {
+	return country.Normalise(h.Country)
+}
+
+// ValidatePostCode checks the post code against the postcode grammar for the hirer's country, countries without
+// postcode rules are accepted as long as the country itself is recognised
+func (h HirerInformation) ValidatePostCode() error {
+	_, err := h.normalisedPostCode()
+	return err
+}
+
+// NormalisePostCode rewrites the country as an ISO 3166-1 alpha-2 code and the post code in the standard format for
+// that country, neither is changed if they are not valid
+func (h *HirerInformation) NormalisePostCode() error {
+	pc, err := h.normalisedPostCode()
+	if err != nil {
+		return err
+	}
+	if len(h.Country) > 0 {
+		h.Country, _ = h.CountryCode()
+	}
+	h.PostCode = pc
+	return nil
+}
+
+// IsOutsideUKJurisdiction returns true if the hirer's address is outside the jurisdiction of the UK courts, either
+// because the country is not the UK, the country is not recognised, or the postcode is in a Crown Dependency
+func (h HirerInformation) IsOutsideUKJurisdiction() bool {
+
+	code, err := h.CountryCode()
+	if err != nil || !country.IsUKJurisdiction(code) {
+		return true
+	}
+
+	j, err := postcode.JurisdictionOf(h.PostCode)
+	return err == nil && j == postcode.CrownDependency
+}
+
+func (h HirerInformation) normalisedPostCode() (string, error) {
+
+	code, err := h.CountryCode()
+	if err != nil {
+		return "", err
+	}
+
+	pc, err := postcode.NormaliseFor(code, h.PostCode)
+	if errors.Is(err, postcode.ErrUnsupportedCountry) {
+		return strings.ToUpper(strings.TrimSpace(h.PostCode)), nil
+	}
+
+	return pc, err
+}
+
+// PostalAddress returns the hirer address ready for formatting with address.Format
+func (h HirerInformation) PostalAddress() address.Address {
+	return address.Address{
+		Name:         strings.Join(strings.Fields(h.Title+" "+h.Name+" "+h.Surname), " "),
+		Organisation: h.CompanyName,
+		Lines:        []string{h.AddressLine1, h.AddressLine2, h.AddressLine3, h.AddressLine4},
+		Postcode:     h.PostCode,
+		Country:      h.Country,
+	}
+}
+
 func GetHirer(ctx context.Context, sref string, client *firestore.Client) (HirerInformation, error) {
 
 	hirerData := struct {
@@ -0,0 +1,148 @@
+package parking_charge_notice
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var titles = map[string]string{
+	"MR": "Mr", "MRS": "Mrs", "MS": "Ms", "MISS": "Miss", "MX": "Mx", "DR": "Dr", "PROF": "Prof",
+	"SIR": "Sir", "DAME": "Dame", "LORD": "Lord", "LADY": "Lady", "REV": "Rev", "REVD": "Revd",
+	"CAPT": "Capt", "MASTER": "Master",
+}
+
+var surnameParticles = map[string]bool{
+	"DA": true, "DE": true, "DEL": true, "DELLA": true, "DEN": true, "DER": true, "DI": true, "DU": true,
+	"LA": true, "LE": true, "ST": true, "VAN": true, "VON": true,
+}
+
+// companyWords - legal suffixes and words which only appear in organisation names, words such as HIRE or MOTORS
+// are left out as they are also surnames or appear in sole trader names
+var companyWords = regexp.MustCompile(`(?i)(\b(LTD|LIMITED|PLC|LLP|LLC|INC|CIC|CORP|CORPORATION|GMBH|BV|SARL|LP|` +
+	`AND CO|COMPANY|HOLDINGS|SOLUTIONS|LEASING|RENTALS?|LOGISTICS|PARTNERSHIP|PARTNERS|COUNCIL|TRUST|ASSOCIATION|` +
+	`UNIVERSITY|NHS)\b|&\s*CO\b)\.?`)
+
+// tradingAs - separates a person from their business name, a bare "ta" is not used as it is also a name
+var tradingAs = regexp.MustCompile(`(?i)\s+(t/a|t\.a\.?|trading as)\s+`)
+
+// IsCompanyName returns true if a name looks like an organisation rather than a person
+func IsCompanyName(name string) bool {
+	return companyWords.MatchString(name)
+}
+
+// NormaliseName tidies the hirer name fields returned by lease companies. A full name held in Name is split into
+// forename and surname with any title moved to Title, names which are really companies are moved to CompanyName and
+// "trading as" names are split between the person and the company.
+func (h *HirerInformation) NormaliseName() {
+
+	name := strings.Join(strings.Fields(h.Name), " ")
+	surname := strings.Join(strings.Fields(h.Surname), " ")
+	company := strings.Join(strings.Fields(h.CompanyName), " ")
+
+	if len(surname) > 0 && IsCompanyName(name+" "+surname) && len(company) == 0 {
+		company = strings.TrimSpace(name + " " + surname)
+		name, surname = "", ""
+	}
+
+	if parts := tradingAs.Split(name, 2); len(parts) == 2 {
+		name = parts[0]
+		if len(company) == 0 {
+			company = parts[1]
+		}
+	}
+
+	if IsCompanyName(name) {
+		if len(company) == 0 {
+			company = name
+		}
+		name = ""
+	}
+
+	title, forename, last := splitName(name)
+	if len(surname) == 0 {
+		surname = last
+	} else if len(last) > 0 {
+		forename = strings.TrimSpace(forename + " " + last)
+	}
+
+	if len(title) > 0 {
+		h.Title = title
+	}
+	h.Name = properCase(forename)
+	h.Surname = properCase(surname)
+	h.CompanyName = company
+}
+
+// Salutation returns the greeting to use on a letter to the hirer, e.g. "Dear Mr Smith"
+func (h HirerInformation) Salutation() string {
+
+	surname := strings.TrimSpace(h.Surname)
+	forename := strings.TrimSpace(h.Name)
+
+	switch {
+	case len(h.Title) > 0 && len(surname) > 0:
+		return "Dear " + h.Title + " " + surname
+	case len(surname) > 0 || len(forename) > 0:
+		return strings.TrimSpace("Dear " + forename + " " + surname)
+	}
+
+	return "Dear Sir or Madam"
+}
+
+func splitName(name string) (title string, forename string, surname string) {
+
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return "", "", ""
+	}
+
+	// "Smith, John" style
+	if i := strings.Index(name, ","); i > 0 {
+		name = strings.TrimSpace(name[i+1:]) + " " + strings.TrimSpace(name[:i])
+	}
+
+	words := strings.Fields(name)
+	if t, ok := titles[strings.ToUpper(strings.TrimSuffix(words[0], "."))]; ok && len(words) > 1 {
+		title = t
+		words = words[1:]
+	}
+
+	if len(words) == 1 {
+		return title, "", words[0]
+	}
+
+	start := len(words) - 1
+	for start > 1 && surnameParticles[strings.ToUpper(words[start-1])] {
+		start--
+	}
+
+	return title, strings.Join(words[:start], " "), strings.Join(words[start:], " ")
+}
+
+// properCase fixes names supplied entirely in upper or lower case, mixed case names are left as given
+func properCase(name string) string {
+
+	if name != strings.ToUpper(name) && name != strings.ToLower(name) {
+		return name
+	}
+
+	out := []rune(strings.ToLower(name))
+	upperNext := true
+	for i, r := range out {
+		if upperNext && unicode.IsLetter(r) {
+			out[i] = unicode.ToUpper(r)
+		}
+		upperNext = r == ' ' || r == '-' || r == '\''
+	}
+
+	// McDonald, Mac is left alone as Mack and Macey are as common as MacKenzie
+	s := string(out)
+	for _, w := range strings.Fields(s) {
+		if strings.HasPrefix(w, "Mc") && len(w) > 3 {
+			s = strings.Replace(s, w, "Mc"+strings.ToUpper(w[2:3])+w[3:], 1)
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,89 @@
+package parking_charge_notice
+
+import "testing"
+
+func TestIsCompanyName(t *testing.T) {
+
+	tests := map[string]bool{
+		"Acme Leasing Ltd":         true,
+		"ACME LIMITED":             true,
+		"Smith & Co":               true,
+		"Smith &Co.":               true,
+		"Smith and Co":             true,
+		"Leeds City Council":       true,
+		"John Smith":               false,
+		"Mary Hire":                false,
+		"Jones Motors":             false,
+		"Smith Transport Services": false,
+		"Anna Group":               false,
+		"Smith & Cooper":           false,
+		"Incerto Smith":            false,
+	}
+
+	for name, want := range tests {
+		if got := IsCompanyName(name); got != want {
+			t.Errorf("IsCompanyName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestNormaliseName(t *testing.T) {
+
+	tests := []struct {
+		name string
+		in   HirerInformation
+		want HirerInformation
+	}{
+		{
+			"full name with title",
+			HirerInformation{Name: "MR JOHN SMITH"},
+			HirerInformation{Title: "Mr", Name: "John", Surname: "Smith"},
+		},
+		{
+			"surname first",
+			HirerInformation{Name: "smith, john"},
+			HirerInformation{Name: "John", Surname: "Smith"},
+		},
+		{
+			"particle",
+			HirerInformation{Name: "Pieter van der Berg"},
+			HirerInformation{Name: "Pieter", Surname: "van der Berg"},
+		},
+		{
+			"company",
+			HirerInformation{Name: "Acme Leasing Ltd"},
+			HirerInformation{CompanyName: "Acme Leasing Ltd"},
+		},
+		{
+			"trading as",
+			HirerInformation{Name: "John Smith t/a Smith Motors"},
+			HirerInformation{Name: "John", Surname: "Smith", CompanyName: "Smith Motors"},
+		},
+		{
+			"trading as dotted",
+			HirerInformation{Name: "John Smith T.A. Smith Motors"},
+			HirerInformation{Name: "John", Surname: "Smith", CompanyName: "Smith Motors"},
+		},
+		{
+			"ta is a name",
+			HirerInformation{Name: "Nguyen Ta Minh"},
+			HirerInformation{Name: "Nguyen Ta", Surname: "Minh"},
+		},
+		{
+			"mc surname",
+			HirerInformation{Name: "JOHN MCDONALD"},
+			HirerInformation{Name: "John", Surname: "McDonald"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.in
+			h.NormaliseName()
+			if h.Title != tt.want.Title || h.Name != tt.want.Name || h.Surname != tt.want.Surname || h.CompanyName != tt.want.CompanyName {
+				t.Errorf("got %q %q %q %q, want %q %q %q %q", h.Title, h.Name, h.Surname, h.CompanyName,
+					tt.want.Title, tt.want.Name, tt.want.Surname, tt.want.CompanyName)
+			}
+		})
+	}
+}
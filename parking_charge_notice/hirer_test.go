@@ -0,0 +1,35 @@
+package parking_charge_notice
+
+import "testing"
+
+func TestIsOutsideUKJurisdiction(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		country  string
+		postCode string
+		want     bool
+	}{
+		{"UK address", "GB", "SW1A 1AA", false},
+		{"no country is the UK", "", "LS1 4DY", false},
+		{"Scotland", "Scotland", "EH1 1YZ", false},
+		{"Northern Ireland", "UK", "BT1 5GS", false},
+		{"unreadable UK postcode", "GB", "not a postcode", false},
+		{"Jersey postcode with no country", "", "JE2 3AB", true},
+		{"Guernsey postcode given as the UK", "United Kingdom", "GY1 1AA", true},
+		{"Isle of Man postcode given as GB", "GB", "IM1 1AA", true},
+		{"Isle of Man country", "Isle of Man", "IM1 1AA", true},
+		{"Republic of Ireland", "Republic of Ireland", "D02 X285", true},
+		{"ROI", "ROI", "", true},
+		{"unknown country", "Atlantis", "SW1A 1AA", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := HirerInformation{Country: tt.country, PostCode: tt.postCode}
+			if got := h.IsOutsideUKJurisdiction(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
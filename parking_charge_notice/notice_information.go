@@ -1,18 +1,24 @@
 package parking_charge_notice
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/asaskevich/govalidator"
 	"github.com/go-playground/validator/v10"
 	joonix "github.com/joonix/log"
 	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/apikey"
+	"github.com/transfer360/go-transfer360/calendar"
+	"github.com/transfer360/go-transfer360/charge"
+	"github.com/transfer360/go-transfer360/evidence"
+	"github.com/transfer360/go-transfer360/jurisdiction"
+	"github.com/transfer360/go-transfer360/notices"
+	"github.com/transfer360/go-transfer360/pofa"
+	"github.com/transfer360/go-transfer360/postcode"
+	"github.com/transfer360/go-transfer360/software_provider"
+	"github.com/transfer360/go-transfer360/vrm"
 	pcn "github.com/transfer360/sys360/notices/parking_charge_notice"
 	"golang.org/x/net/context"
-	"io"
-	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -20,16 +26,55 @@ import (
 
 type Information struct {
 	pcn.Data
+	// ISO 3166-1 alpha-2 country of the vehicle registration - optional, defaults to GB
+	VRMCountry string `json:"vrm_country,omitempty"`
+	// how the driver was first notified - optional, when set Validate warns if the notice is too late for POFA
+	// hirer liability
+	PofaNoticeType pofa.NoticeType `json:"-"`
+	// when a windscreen notice was given to the driver - optional, defaults to the contravention date
+	NoticeToDriverGiven time.Time `json:"-"`
+	// jurisdiction of the site, e.g. "scotland" - optional, detected from Location.PostCode or England and Wales is
+	// assumed
+	Jurisdiction string `json:"-"`
+	// evidence files - optional, add with Attach, details are sent with the notice and the files uploaded after it
+	Evidence []evidence.Attachment `json:"evidence,omitempty"`
+	// warnings raised by Validate which do not stop the notice being sent
+	Warnings []string `json:"-"`
+	// caps the charge amounts are validated against - optional, defaults to charge.DefaultCaps
+	ChargeCaps *charge.Caps `json:"-"`
 }
 
-var ErrNoticeAlreadyExists = errors.New("notice already exists")
-var ErrIssuerNotSetup = errors.New("issuer is not setup")
+var ErrNoticeAlreadyExists = notices.ErrNoticeAlreadyExists
+var ErrIssuerNotSetup = notices.ErrIssuerNotSetup
+var ErrNoChargeSchedule = errors.New("notice has no charge amounts")
+var ErrNoIssueDate = errors.New("notice has no issue date")
+var ErrLikelyDuplicate = notices.ErrLikelyDuplicate
+var ErrEvidenceNotUploaded = notices.ErrEvidenceNotUploaded
+
+func init() {
+	notices.Register(notices.TypeParkingCharge, func() notices.Notice { return &Information{} })
+}
+
+// Type returns notices.TypeParkingCharge, Information can be sent with notices.Send as well as Send
+func (notice *Information) Type() notices.Type {
+	return notices.TypeParkingCharge
+}
+
+// Endpoint returns the api path parking charge notices are posted to
+func (notice *Information) Endpoint() string {
+	return "/notice/parking_charge"
+}
 
 // Validate ----------------------------------------------------------------------------------------------------------
 func (notice *Information) Validate() error {
 
 	dateNow := time.Now()
 
+	country, err := vrm.NormaliseCountryCode(notice.VRMCountry)
+	if err != nil {
+		return err
+	}
+
 	if !govalidator.IsRFC3339(notice.ContraventionDateTime) {
 
 		notice.ContraventionDateTime = strings.ReplaceAll(notice.ContraventionDateTime, "T", " ")
@@ -73,83 +118,173 @@ func (notice *Information) Validate() error {
 		}
 	}
 
+	if len(notice.IssueDate) > 0 {
+		if _, err := notice.issueDate(); err != nil {
+			return err
+		}
+	}
+
+	if schedule := notice.ChargeSchedule(); schedule != nil {
+		if err := schedule.Validate(notice.chargeCaps()); err != nil {
+			return err
+		}
+	}
+
+	if err := evidence.CheckLimits(notice.Evidence, evidence.DefaultLimits); err != nil {
+		return err
+	}
+
+	notice.Warnings = nil
+
+	if len(notice.VehicleRegistration) > 0 {
+		if _, err := vrm.Normalise(notice.VehicleRegistration, country); err != nil {
+			notice.warn(err.Error())
+		}
+	}
+
+	if len(notice.PofaNoticeType) > 0 {
+		if err := notice.checkKeeperLiability(cDateTime, dateNow); err != nil {
+			return err
+		}
+	}
+
 	validate := validator.New()
 	return validate.Struct(notice)
 
 }
 
-// Send ----------------------------------------------------------------------------------------------------------
-func (notice *Information) Send(apiKey string) error {
+// checkKeeperLiability warns when the site is in a jurisdiction without POFA keeper and hirer liability, or when the
+// notice to keeper deadline has passed. A site postcode which cannot be read is warned about and England and Wales
+// is assumed, only an explicit Jurisdiction which cannot be parsed or disagrees with the postcode is an error.
+func (notice *Information) checkKeeperLiability(contravention time.Time, now time.Time) error {
 
-	if len(os.Getenv("DEVELOPMENT")) == 0 {
-		log.SetFormatter(joonix.NewFormatter())
+	j, err := jurisdiction.Detect(notice.Jurisdiction, notice.Location.PostCode)
+	if errors.Is(err, postcode.ErrInvalidPostcode) {
+		notice.warn(fmt.Sprintf("site postcode %q could not be read, the POFA deadline assumes the site is in %s", notice.Location.PostCode, notice.jurisdictionName()))
+		j, err = jurisdiction.Detect(notice.Jurisdiction, "")
+	}
+	if err != nil {
+		return err
 	}
 
-	log.SetLevel(log.DebugLevel)
-	log.SetReportCaller(true)
+	rules, err := jurisdiction.RulesFor(j)
+	if errors.Is(err, jurisdiction.ErrUnsupportedJurisdiction) {
+		notice.warn(fmt.Sprintf("POFA keeper and hirer liability does not apply in %s, only the driver can be pursued", j))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 
-	err := notice.Validate()
+	if !rules.KeeperLiability {
+		notice.warn(fmt.Sprintf("POFA keeper and hirer liability does not apply in %s, only the driver can be pursued", rules.Name()))
+		return nil
+	}
 
-	if err != nil {
-		return fmt.Errorf("go-transfer360 information invalid: %w", err)
+	return notice.checkPofaDeadline(contravention, now, rules.Calendar)
+}
+
+func (notice *Information) jurisdictionName() string {
+	if len(notice.Jurisdiction) > 0 {
+		return notice.Jurisdiction
 	}
+	return "England and Wales"
+}
+
+// checkPofaDeadline warns when a notice sent now would not be given to the keeper within the POFA Schedule 4 period,
+// without which the hirer cannot be held liable
+func (notice *Information) checkPofaDeadline(contravention time.Time, now time.Time, cal calendar.Calendar) error {
 
-	sendURL := "https://api.transfer360.io/notice/parking_charge"
-	noticeData, err := json.Marshal(notice)
+	d, err := pofa.Calculate(pofa.Input{
+		NoticeType:            notice.PofaNoticeType,
+		ContraventionDateTime: contravention,
+		NoticeToDriverGiven:   notice.NoticeToDriverGiven,
+	}, now, cal)
 	if err != nil {
-		log.Errorln(err)
 		return err
 	}
 
-	log.Debugln(string(noticeData))
+	if d.NoticeToKeeper.HolidaysUnknown {
+		notice.warn(fmt.Sprintf("no bank holidays are known for %d, load them with calendar.LoadFile so the notice to keeper deadline is correct", d.NoticeToKeeper.Latest.Year()))
+	}
+	if d.NoticeToKeeper.TooLateToPost {
+		notice.warn(fmt.Sprintf("notice to keeper deadline %s has passed, POFA hirer liability will not apply", d.NoticeToKeeper.Latest.Format("2006-01-02")))
+	}
+
+	return nil
+}
+
+// Warn records a warning which does not stop the notice being sent, see Warnings
+func (notice *Information) Warn(warning string) {
+	notice.warn(warning)
+}
+
+func (notice *Information) warn(warning string) {
+	log.Warnf("%s | %s", notice.SearchReference, warning)
+	notice.Warnings = append(notice.Warnings, warning)
+}
+
+// ApplySoftwareProvider ----------------------------------------------------------------------------------------------
+// checks the software provider is enabled for parking charge notices and fills in the provider's notice defaults
+func (notice *Information) ApplySoftwareProvider(ctx context.Context, id int, store software_provider.Store) error {
 
-	req, err := http.NewRequest("POST", sendURL, bytes.NewBuffer(noticeData))
+	p, err := software_provider.GetSoftwareProvider(ctx, id, store)
 	if err != nil {
-		log.Errorln(err)
 		return err
 	}
-	req.Header.Set("api_key", apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
+
+	if err = p.CheckEndpoint(software_provider.EndpointParkingCharge); err != nil {
+		return err
+	}
+
+	if len(notice.VRMCountry) == 0 {
+		notice.VRMCountry = p.NoticeOptions.VRMCountry
+	}
+
+	return nil
+}
+
+// Send ----------------------------------------------------------------------------------------------------------
+func (notice *Information) Send(apiKey string) error {
 
 	if len(os.Getenv("DEVELOPMENT")) == 0 {
-		client.Timeout = time.Second * 20
+		log.SetFormatter(joonix.NewFormatter())
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		if strings.Contains(err.Error(), "context deadline exceeded") || strings.Contains(err.Error(), "Client.Timeout exceeded while awaiting headers") {
-			return context.DeadlineExceeded
-		} else {
-			log.Errorln(err)
-			return fmt.Errorf("sending go-transfer360 to api server %w", err)
-		}
-	} else {
-		defer resp.Body.Close()
+	log.SetLevel(log.DebugLevel)
+	log.SetReportCaller(true)
 
-		body, _ := io.ReadAll(resp.Body)
-		log.Debugf("response Body: %s", string(body))
+	return notice.SendWith(context.Background(), notices.DefaultSender, apiKey)
+}
 
-		if resp.StatusCode != http.StatusOK {
+// SendWith ---------------------------------------------------------------------------------------------------------
+// sends the notice through the given sender, e.g. one checking for duplicates or posting to a fakeserver.Server
+func (notice *Information) SendWith(ctx context.Context, sender *notices.Sender, apiKey string) error {
 
-			if resp.StatusCode == http.StatusConflict {
-				return ErrNoticeAlreadyExists
-			}
-			if resp.StatusCode == http.StatusTooEarly {
-				return ErrIssuerNotSetup
-			}
+	err := notice.Validate()
 
-			log.Warnf("Non-200: %d %s", resp.StatusCode, resp.Status)
-			log.Warnf("%s | %s", notice.SearchReference, string(body))
-			return fmt.Errorf("error code returned from api server (%d)[%s]", resp.StatusCode, string(body))
+	if err != nil {
+		return fmt.Errorf("go-transfer360 information invalid: %w", err)
+	}
 
-		} else {
-			log.Debugln("OK")
-			log.Debugln(string(body))
-		}
+	if err = sender.Send(ctx, notice, apiKey); err != nil {
+		log.Warnf("%s | %v", notice.SearchReference, err)
+		return err
 	}
+
 	return nil
+}
+
+// SendForIssuer ----------------------------------------------------------------------------------------------------
+// sends the notice through notices.DefaultSender using the API key resolved for the issuer
+func (notice *Information) SendForIssuer(ctx context.Context, issuerID string, keys apikey.APIKeyResolver) error {
+
+	apiKey, err := keys.APIKey(ctx, issuerID)
+	if err != nil {
+		return fmt.Errorf("resolving API key: %w", err)
+	}
 
+	return notice.SendWith(ctx, notices.DefaultSender, apiKey)
 }
 
 // ----------------------------------------------------------------------------------------------------------
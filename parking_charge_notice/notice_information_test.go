@@ -0,0 +1,105 @@
+package parking_charge_notice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/transfer360/go-transfer360/apikey"
+	"github.com/transfer360/go-transfer360/jurisdiction"
+	"github.com/transfer360/go-transfer360/pofa"
+)
+
+func TestValidateJurisdiction(t *testing.T) {
+
+	contravention := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name         string
+		postcode     string
+		jurisdiction string
+		pofa         bool
+		warning      string
+		err          error
+	}{
+		{"no pofa with partial postcode", "LS1", "", false, "", nil},
+		{"no pofa in jersey", "JE2 3QN", "", false, "", nil},
+		{"no pofa with foreign postcode", "75001", "", false, "", nil},
+		{"pofa in england", "LS1 4DY", "", true, "", nil},
+		{"pofa with partial postcode", "LS1", "", true, "could not be read", nil},
+		{"pofa in jersey", "JE2 3QN", "", true, "does not apply in crown_dependency", nil},
+		{"pofa in scotland", "EH1 1YZ", "", true, "does not apply in Scotland", nil},
+		{"pofa with jurisdiction disagreeing with postcode", "LS1 4DY", "scotland", true, "", jurisdiction.ErrJurisdictionMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			notice, err := NewBuilder().
+				SearchReference("SREF1").
+				VRM("AB12CDE", "").
+				ContraventionAt(contravention).
+				Site("Station Road Car Park", "").
+				Charge(10000, 6000, 14).
+				IssuedOn(contravention).
+				Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			notice.Location.PostCode = tt.postcode
+			notice.Jurisdiction = tt.jurisdiction
+			if tt.pofa {
+				notice.PofaNoticeType = pofa.ANPR
+			}
+
+			err = notice.Validate()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			found := len(tt.warning) == 0 && len(notice.Warnings) == 0
+			for _, w := range notice.Warnings {
+				if len(tt.warning) > 0 && strings.Contains(w, tt.warning) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("got warnings %v, want %q", notice.Warnings, tt.warning)
+			}
+		})
+	}
+}
+
+func TestSendForIssuer(t *testing.T) {
+
+	contravention := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+	notice, err := NewBuilder().
+		SearchReference("SREF1").
+		VRM("AB12CDE", "").
+		ContraventionAt(contravention).
+		Charge(10000, 6000, 14).
+		IssuedOn(contravention).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := apikey.StaticResolver{"ISSUER1": "key-one"}
+
+	if err = notice.SendForIssuer(context.Background(), "ISSUER2", keys); !errors.Is(err, apikey.ErrUnknownIssuer) {
+		t.Errorf("got %v, want %v", err, apikey.ErrUnknownIssuer)
+	}
+
+	// the caller's context is used for the request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err = notice.SendForIssuer(ctx, "ISSUER1", keys); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,153 @@
+// Package pofa calculates the notice deadlines set by Schedule 4 of the Protection of Freedoms Act 2012 for keeper
+// and hirer liability for unpaid parking charges
+package pofa
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/transfer360/go-transfer360/calendar"
+)
+
+var ErrUnknownNoticeType = errors.New("unknown notice type")
+var ErrMissingContraventionDate = errors.New("missing contravention date")
+
+// NoticeType - how the driver was first notified, which sets the paragraph of Schedule 4 the notice to keeper falls under
+type NoticeType string
+
+const (
+	// ANPR - no notice given to the driver at the time, paragraph 9
+	ANPR NoticeType = "anpr"
+	// Windscreen - a notice to driver affixed to the vehicle or handed to the driver, paragraph 8
+	Windscreen NoticeType = "windscreen"
+)
+
+const (
+	// anprKeeperDays - paragraph 9(5), notice to keeper given within 14 days beginning with the day after parking ended
+	anprKeeperDays = 14
+	// windscreenKeeperEarliestDays - paragraph 8(5), notice to keeper not given before 28 days after the notice to driver
+	windscreenKeeperEarliestDays = 28
+	// windscreenKeeperLatestDays - paragraph 8(5), nor later than 56 days after the notice to driver
+	windscreenKeeperLatestDays = 56
+	// hirerDays - paragraph 14(2)(b), notice to hirer given within 21 days beginning with the day after the hire
+	// documents were received
+	hirerDays = 21
+)
+
+// Input - what is known about a parking event
+type Input struct {
+	NoticeType NoticeType
+	// ContraventionDateTime - when the period of parking ended
+	ContraventionDateTime time.Time
+	// NoticeToDriverGiven - when a windscreen notice was given, defaults to ContraventionDateTime
+	NoticeToDriverGiven time.Time
+	// KeeperIdentified - when the search result identifying the keeper was obtained, optional
+	KeeperIdentified time.Time
+	// HirerDocumentsReceived - when the hirer information was received from the lease company, optional
+	HirerDocumentsReceived time.Time
+}
+
+// Deadline - the window in which a notice must be given, dates are calendar days in UK time
+type Deadline struct {
+	// Earliest - first day the notice may be given, nil if there is no earliest date
+	Earliest *time.Time `json:"earliest,omitempty"`
+	// Latest - last day the notice may be given
+	Latest time.Time `json:"latest"`
+	// LatestPosting - last day a notice may be posted to be presumed given by Latest
+	LatestPosting time.Time `json:"latest_posting"`
+	// DaysRemaining - calendar days from today until Latest, negative once overdue
+	DaysRemaining int `json:"days_remaining"`
+	// Overdue - Latest has passed
+	Overdue bool `json:"overdue"`
+	// TooLateToPost - a notice posted today would not be presumed given by Latest
+	TooLateToPost bool `json:"too_late_to_post"`
+	// HolidaysUnknown - Latest is after the last year the calendar has bank holidays for, so LatestPosting ignores
+	// any bank holidays and may be too late
+	HolidaysUnknown bool `json:"holidays_unknown,omitempty"`
+}
+
+// Deadlines - the Schedule 4 deadlines for a parking event
+type Deadlines struct {
+	NoticeToKeeper Deadline `json:"notice_to_keeper"`
+	// NoticeToHirer - only set once the hirer documents have been received
+	NoticeToHirer *Deadline `json:"notice_to_hirer,omitempty"`
+	// KeeperIdentifiedDaysAfterParking - days between parking ending and the keeper being identified, -1 if unknown
+	KeeperIdentifiedDaysAfterParking int `json:"keeper_identified_days_after_parking"`
+}
+
+// Calendar - working days for postal delivery, nil uses the England and Wales calendar
+type Calendar interface {
+	LatestPosting(deadline time.Time) time.Time
+	Covers(t time.Time) bool
+}
+
+// Calculate returns the notice to keeper and notice to hirer deadlines as at now
+func Calculate(in Input, now time.Time, cal Calendar) (Deadlines, error) {
+
+	if in.ContraventionDateTime.IsZero() {
+		return Deadlines{}, ErrMissingContraventionDate
+	}
+
+	d := Deadlines{KeeperIdentifiedDaysAfterParking: -1}
+	today := day(now)
+	parked := day(in.ContraventionDateTime)
+
+	switch in.NoticeType {
+	case ANPR:
+		d.NoticeToKeeper = deadline(nil, lastDayOfPeriod(parked, anprKeeperDays), today, cal)
+	case Windscreen:
+		ntd := parked
+		if !in.NoticeToDriverGiven.IsZero() {
+			ntd = day(in.NoticeToDriverGiven)
+		}
+		earliest := ntd.AddDate(0, 0, windscreenKeeperEarliestDays+1)
+		d.NoticeToKeeper = deadline(&earliest, lastDayOfPeriod(ntd, windscreenKeeperLatestDays), today, cal)
+	default:
+		return d, fmt.Errorf("%w [%s]", ErrUnknownNoticeType, in.NoticeType)
+	}
+
+	if !in.KeeperIdentified.IsZero() {
+		d.KeeperIdentifiedDaysAfterParking = daysBetween(parked, day(in.KeeperIdentified))
+	}
+
+	if !in.HirerDocumentsReceived.IsZero() {
+		nth := deadline(nil, lastDayOfPeriod(day(in.HirerDocumentsReceived), hirerDays), today, cal)
+		d.NoticeToHirer = &nth
+	}
+
+	return d, nil
+}
+
+// lastDayOfPeriod returns the last day of a period of n days beginning with the day after start
+func lastDayOfPeriod(start time.Time, n int) time.Time {
+	return start.AddDate(0, 0, n)
+}
+
+func deadline(earliest *time.Time, latest, today time.Time, cal Calendar) Deadline {
+
+	if cal == nil {
+		cal = calendar.EnglandAndWales()
+	}
+
+	// paragraph 9(6), a posted notice is presumed given on the second working day after posting
+	posting := cal.LatestPosting(latest)
+
+	return Deadline{
+		Earliest:        earliest,
+		Latest:          latest,
+		LatestPosting:   posting,
+		DaysRemaining:   daysBetween(today, latest),
+		Overdue:         today.After(latest),
+		TooLateToPost:   today.After(posting),
+		HolidaysUnknown: !cal.Covers(latest),
+	}
+}
+
+func day(t time.Time) time.Time {
+	return calendar.Day(t)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
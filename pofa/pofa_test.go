@@ -0,0 +1,156 @@
+package pofa
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/transfer360/go-transfer360/calendar"
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCalculate(t *testing.T) {
+
+	scotland, err := calendar.For(postcode.Scotland)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		in            Input
+		now           string
+		cal           Calendar
+		earliest      string
+		latest        string
+		latestPosting string
+		remaining     int
+		overdue       bool
+		tooLate       bool
+	}{
+		{
+			name:          "anpr",
+			in:            Input{NoticeType: ANPR, ContraventionDateTime: date("2025-06-01")},
+			now:           "2025-06-05",
+			latest:        "2025-06-15",
+			latestPosting: "2025-06-11",
+			remaining:     10,
+		},
+		{
+			name:          "anpr too late to post",
+			in:            Input{NoticeType: ANPR, ContraventionDateTime: date("2025-06-01")},
+			now:           "2025-06-12",
+			latest:        "2025-06-15",
+			latestPosting: "2025-06-11",
+			remaining:     3,
+			tooLate:       true,
+		},
+		{
+			name:          "anpr overdue",
+			in:            Input{NoticeType: ANPR, ContraventionDateTime: date("2025-06-01")},
+			now:           "2025-06-16",
+			latest:        "2025-06-15",
+			latestPosting: "2025-06-11",
+			remaining:     -1,
+			overdue:       true,
+			tooLate:       true,
+		},
+		{
+			name:          "anpr scottish bank holiday",
+			in:            Input{NoticeType: ANPR, ContraventionDateTime: date("2025-07-22")},
+			now:           "2025-07-23",
+			cal:           scotland,
+			latest:        "2025-08-05",
+			latestPosting: "2025-07-31",
+			remaining:     13,
+		},
+		{
+			name:          "windscreen",
+			in:            Input{NoticeType: Windscreen, ContraventionDateTime: date("2025-06-01"), NoticeToDriverGiven: date("2025-06-02")},
+			now:           "2025-06-05",
+			earliest:      "2025-07-01",
+			latest:        "2025-07-28",
+			latestPosting: "2025-07-24",
+			remaining:     53,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			d, err := Calculate(tt.in, date(tt.now), tt.cal)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			k := d.NoticeToKeeper
+			if len(tt.earliest) > 0 && (k.Earliest == nil || !k.Earliest.Equal(date(tt.earliest))) {
+				t.Errorf("earliest got %v, want %s", k.Earliest, tt.earliest)
+			}
+			if len(tt.earliest) == 0 && k.Earliest != nil {
+				t.Errorf("unexpected earliest %s", k.Earliest)
+			}
+			if !k.Latest.Equal(date(tt.latest)) {
+				t.Errorf("latest got %s, want %s", k.Latest.Format("2006-01-02"), tt.latest)
+			}
+			if !k.LatestPosting.Equal(date(tt.latestPosting)) {
+				t.Errorf("latest posting got %s, want %s", k.LatestPosting.Format("2006-01-02"), tt.latestPosting)
+			}
+			if k.DaysRemaining != tt.remaining || k.Overdue != tt.overdue || k.TooLateToPost != tt.tooLate {
+				t.Errorf("got %+v", k)
+			}
+			if k.HolidaysUnknown {
+				t.Error("unexpected unknown holidays")
+			}
+		})
+	}
+}
+
+func TestCalculateHirer(t *testing.T) {
+
+	d, err := Calculate(Input{
+		NoticeType:             ANPR,
+		ContraventionDateTime:  date("2025-06-01"),
+		KeeperIdentified:       date("2025-06-04"),
+		HirerDocumentsReceived: date("2025-06-10"),
+	}, date("2025-06-10"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.KeeperIdentifiedDaysAfterParking != 3 {
+		t.Errorf("keeper identified after %d days, want 3", d.KeeperIdentifiedDaysAfterParking)
+	}
+	if d.NoticeToHirer == nil || !d.NoticeToHirer.Latest.Equal(date("2025-07-01")) {
+		t.Errorf("unexpected notice to hirer deadline %+v", d.NoticeToHirer)
+	}
+}
+
+func TestCalculateBeyondHolidayData(t *testing.T) {
+
+	d, err := Calculate(Input{NoticeType: ANPR, ContraventionDateTime: date("2027-12-25")}, date("2027-12-27"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.NoticeToKeeper.HolidaysUnknown {
+		t.Errorf("expected a deadline of %s to be beyond the holiday data", d.NoticeToKeeper.Latest.Format("2006-01-02"))
+	}
+}
+
+func TestCalculateErrors(t *testing.T) {
+
+	if _, err := Calculate(Input{NoticeType: ANPR}, time.Now(), nil); !errors.Is(err, ErrMissingContraventionDate) {
+		t.Errorf("got %v, want %v", err, ErrMissingContraventionDate)
+	}
+	if _, err := Calculate(Input{NoticeType: "letter", ContraventionDateTime: time.Now()}, time.Now(), nil); !errors.Is(err, ErrUnknownNoticeType) {
+		t.Errorf("got %v, want %v", err, ErrUnknownNoticeType)
+	}
+}
@@ -0,0 +1,82 @@
+package postcode
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrUnsupportedCountry - error raised when there are no postcode rules for a country
+var ErrUnsupportedCountry = errors.New("postcode rules not available for country")
+
+type format struct {
+	pattern *regexp.Regexp
+	// render builds the normalised postcode from the compacted postcode
+	render func(pc string) string
+}
+
+func asIs(pc string) string {
+	return pc
+}
+
+func splitAt(n int, sep string) func(string) string {
+	return func(pc string) string {
+		return pc[:n] + sep + pc[n:]
+	}
+}
+
+// foreignFormats - postcode formats keyed by ISO 3166-1 alpha-2 code, patterns match the postcode with spaces and
+// hyphens removed
+var foreignFormats = map[string]format{
+	"IE": {regexp.MustCompile(`^([AC-FHKNPRTV-Y][0-9]{2}|D6W)[0-9AC-FHKNPRTV-Y]{4}$`), splitAt(3, " ")}, // Eircode
+	"FR": {regexp.MustCompile(`^[0-9]{5}$`), asIs},
+	"DE": {regexp.MustCompile(`^[0-9]{5}$`), asIs},
+	"IT": {regexp.MustCompile(`^[0-9]{5}$`), asIs},
+	"ES": {regexp.MustCompile(`^(0[1-9]|[1-4][0-9]|5[0-2])[0-9]{3}$`), asIs},
+	"NL": {regexp.MustCompile(`^[1-9][0-9]{3}[A-Z]{2}$`), splitAt(4, " ")},
+	"BE": {regexp.MustCompile(`^[1-9][0-9]{3}$`), asIs},
+	"LU": {regexp.MustCompile(`^(L)?[0-9]{4}$`), func(pc string) string { return "L-" + strings.TrimPrefix(pc, "L") }},
+	"AT": {regexp.MustCompile(`^[1-9][0-9]{3}$`), asIs},
+	"CH": {regexp.MustCompile(`^[1-9][0-9]{3}$`), asIs},
+	"DK": {regexp.MustCompile(`^[1-9][0-9]{3}$`), asIs},
+	"PT": {regexp.MustCompile(`^[1-9][0-9]{6}$`), splitAt(4, "-")},
+	"PL": {regexp.MustCompile(`^[0-9]{5}$`), splitAt(2, "-")},
+	"SE": {regexp.MustCompile(`^[1-9][0-9]{4}$`), splitAt(3, " ")},
+	"NO": {regexp.MustCompile(`^[0-9]{4}$`), asIs},
+	"FI": {regexp.MustCompile(`^[0-9]{5}$`), asIs},
+	"CZ": {regexp.MustCompile(`^[1-7][0-9]{4}$`), splitAt(3, " ")},
+}
+
+// ukFormatCountries - countries using the UK postcode grammar
+var ukFormatCountries = map[string]bool{"GB": true, "GG": true, "JE": true, "IM": true}
+
+// NormaliseFor validates and normalises a postcode for the given ISO 3166-1 alpha-2 country code. UK and Crown
+// Dependency postcodes use Normalise, countries without rules return ErrUnsupportedCountry.
+func NormaliseFor(countryCode string, postcode string) (string, error) {
+
+	countryCode = strings.ToUpper(strings.TrimSpace(countryCode))
+
+	if ukFormatCountries[countryCode] {
+		return Normalise(postcode)
+	}
+
+	f, ok := foreignFormats[countryCode]
+	if !ok {
+		return "", fmt.Errorf("%w [%s]", ErrUnsupportedCountry, countryCode)
+	}
+
+	pc := compact(postcode)
+	if !f.pattern.MatchString(pc) {
+		return "", fmt.Errorf("%w [%s] for country %s", ErrInvalidPostcode, postcode, countryCode)
+	}
+
+	return f.render(pc), nil
+}
+
+// IsSupportedCountry returns true if NormaliseFor has postcode rules for the country
+func IsSupportedCountry(countryCode string) bool {
+	countryCode = strings.ToUpper(countryCode)
+	_, ok := foreignFormats[countryCode]
+	return ok || ukFormatCountries[countryCode]
+}
@@ -0,0 +1,130 @@
+// Package postcode validates and normalises UK postcodes and identifies which legal jurisdiction they fall in
+package postcode
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidPostcode - error raised when a postcode does not match the postcode grammar for its country
+var ErrInvalidPostcode = errors.New("invalid postcode")
+
+// Jurisdiction - legal jurisdiction a postcode falls in
+type Jurisdiction string
+
+const (
+	EnglandAndWales   Jurisdiction = "england_and_wales"
+	Scotland          Jurisdiction = "scotland"
+	NorthernIreland   Jurisdiction = "northern_ireland"
+	CrownDependency   Jurisdiction = "crown_dependency" // Isle of Man and the Channel Islands
+	BritishForcesPost Jurisdiction = "bfpo"
+)
+
+const girobank = "GIR 0AA"
+
+var ukPostcode = regexp.MustCompile(`^(([A-Z][0-9]{1,2})|([A-Z][A-HJ-Y][0-9]{1,2})|([A-Z][0-9][A-Z])|([A-Z][A-HJ-Y][0-9][A-Z]?))([0-9][ABD-HJLNP-UW-Z]{2})$`)
+var bfpoPostcode = regexp.MustCompile(`^BFPO([0-9]{1,4})$`)
+
+var scottishAreas = map[string]bool{
+	"AB": true, "DD": true, "DG": true, "EH": true, "FK": true, "G": true, "HS": true, "IV": true,
+	"KA": true, "KW": true, "KY": true, "ML": true, "PA": true, "PH": true, "TD": true, "ZE": true,
+}
+
+var crownDependencyAreas = map[string]bool{
+	"GY": true, "JE": true, "IM": true,
+}
+
+// districts which fall outside the jurisdiction of the rest of their postcode area
+var crossBorderDistricts = map[string]Jurisdiction{
+	"TD15": EnglandAndWales, // Berwick-upon-Tweed
+}
+
+// Normalise returns the postcode in upper case with a single space between the outward and inward codes,
+// e.g. "sw1a1aa" becomes "SW1A 1AA" and "bfpo123" becomes "BFPO 123"
+func Normalise(postcode string) (string, error) {
+
+	pc := compact(postcode)
+
+	if pc == strings.ReplaceAll(girobank, " ", "") {
+		return girobank, nil
+	}
+
+	if m := bfpoPostcode.FindStringSubmatch(pc); m != nil {
+		return "BFPO " + m[1], nil
+	}
+
+	if !ukPostcode.MatchString(pc) {
+		return "", fmt.Errorf("%w [%s]", ErrInvalidPostcode, postcode)
+	}
+
+	return pc[:len(pc)-3] + " " + pc[len(pc)-3:], nil
+}
+
+// IsValid returns true if the postcode matches the UK postcode grammar, including BFPO and GIR 0AA
+func IsValid(postcode string) bool {
+	_, err := Normalise(postcode)
+	return err == nil
+}
+
+// Outward returns the outward code (district) of the postcode, e.g. "SW1A"
+func Outward(postcode string) (string, error) {
+	pc, err := Normalise(postcode)
+	if err != nil {
+		return "", err
+	}
+	return strings.Fields(pc)[0], nil
+}
+
+// Area returns the letters of the postcode area, e.g. "SW" for "SW1A 1AA", BFPO and GIR 0AA postcodes return
+// "BFPO" and "GIR"
+func Area(postcode string) (string, error) {
+	outward, err := Outward(postcode)
+	if err != nil {
+		return "", err
+	}
+	if outward == "BFPO" || outward == "GIR" {
+		return outward, nil
+	}
+	return strings.TrimRightFunc(outward[:2], func(r rune) bool { return r >= '0' && r <= '9' }), nil
+}
+
+// JurisdictionOf returns the jurisdiction a postcode falls in
+func JurisdictionOf(postcode string) (Jurisdiction, error) {
+
+	outward, err := Outward(postcode)
+	if err != nil {
+		return "", err
+	}
+
+	if outward == "BFPO" {
+		return BritishForcesPost, nil
+	}
+
+	if j, ok := crossBorderDistricts[outward]; ok {
+		return j, nil
+	}
+
+	area, _ := Area(postcode)
+
+	switch {
+	case area == "BT":
+		return NorthernIreland, nil
+	case scottishAreas[area]:
+		return Scotland, nil
+	case crownDependencyAreas[area]:
+		return CrownDependency, nil
+	}
+
+	return EnglandAndWales, nil
+}
+
+func compact(postcode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(postcode)))
+}
@@ -0,0 +1,133 @@
+package postcode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalise(t *testing.T) {
+
+	tests := []struct {
+		postcode string
+		want     string
+		err      error
+	}{
+		{"sw1a1aa", "SW1A 1AA", nil},
+		{" LS1  4DY ", "LS1 4DY", nil},
+		{"m1 1ae", "M1 1AE", nil},
+		{"B33 8TH", "B33 8TH", nil},
+		{"CR2 6XH", "CR2 6XH", nil},
+		{"DN55 1PT", "DN55 1PT", nil},
+		{"W1A 0AX", "W1A 0AX", nil},
+		{"gir0aa", "GIR 0AA", nil},
+		{"bfpo 123", "BFPO 123", nil},
+		{"", "", ErrInvalidPostcode},
+		{"LS1", "", ErrInvalidPostcode},
+		{"LS1 4CY", "", ErrInvalidPostcode},
+		{"1S1 4DY", "", ErrInvalidPostcode},
+		{"12345", "", ErrInvalidPostcode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.postcode, func(t *testing.T) {
+			got, err := Normalise(tt.postcode)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArea(t *testing.T) {
+
+	tests := map[string]string{
+		"SW1A 1AA": "SW",
+		"G1 1XQ":   "G",
+		"M1 1AE":   "M",
+		"EH1 1YZ":  "EH",
+		"BFPO 1":   "BFPO",
+		"GIR 0AA":  "GIR",
+	}
+
+	for pc, want := range tests {
+		got, err := Area(pc)
+		if err != nil {
+			t.Fatalf("Area(%q) error %v", pc, err)
+		}
+		if got != want {
+			t.Errorf("Area(%q) = %q, want %q", pc, got, want)
+		}
+	}
+}
+
+func TestJurisdictionOf(t *testing.T) {
+
+	tests := []struct {
+		postcode string
+		want     Jurisdiction
+	}{
+		{"LS1 4DY", EnglandAndWales},
+		{"CF10 1EP", EnglandAndWales},
+		{"EH1 1YZ", Scotland},
+		{"G1 1XQ", Scotland},
+		{"DG16 5HZ", Scotland},
+		{"TD1 1AA", Scotland},
+		{"TD15 1BT", EnglandAndWales},
+		{"BT1 5GS", NorthernIreland},
+		{"IM1 1AA", CrownDependency},
+		{"JE2 3QN", CrownDependency},
+		{"BFPO 123", BritishForcesPost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.postcode, func(t *testing.T) {
+			got, err := JurisdictionOf(tt.postcode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := JurisdictionOf("not a postcode"); !errors.Is(err, ErrInvalidPostcode) {
+		t.Errorf("got %v, want %v", err, ErrInvalidPostcode)
+	}
+}
+
+func TestNormaliseFor(t *testing.T) {
+
+	tests := []struct {
+		country  string
+		postcode string
+		want     string
+		err      error
+	}{
+		{"GB", "ls14dy", "LS1 4DY", nil},
+		{"je", "JE2 3QN", "JE2 3QN", nil},
+		{"IE", "d02x285", "D02 X285", nil},
+		{"NL", "1012 ab", "1012 AB", nil},
+		{"PT", "1000-001", "1000-001", nil},
+		{"PL", "00950", "00-950", nil},
+		{"LU", "L-1234", "L-1234", nil},
+		{"LU", "1234", "L-1234", nil},
+		{"FR", "7500", "", ErrInvalidPostcode},
+		{"US", "90210", "", ErrUnsupportedCountry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.postcode, func(t *testing.T) {
+			got, err := NormaliseFor(tt.country, tt.postcode)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
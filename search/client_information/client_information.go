@@ -1 +1,141 @@
+// Package client_information looks up Transfer360 clients, the issuers they may act for and their client level
+// settings
 package client_information
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/search"
+	"google.golang.org/api/iterator"
+	"time"
+)
+
+const CLIENTS_COLLECTION = "clients"
+
+// DefaultRetentionDays - how long search records are kept when a client has no retention period set
+const DefaultRetentionDays = 365
+
+var ErrClientNotFound = errors.New("client not found")
+var ErrIssuerNotForClient = errors.New("issuer does not belong to client")
+
+// Settings - client level options
+type Settings struct {
+	// DefaultVRMCountry - vehicle country used for searches which do not give one
+	DefaultVRMCountry string `json:"default_vrm_country,omitempty" firestore:"default_vrm_country,omitempty"`
+	// RetentionDays - how long search records are kept, 0 uses DefaultRetentionDays
+	RetentionDays int `json:"retention_days,omitempty" firestore:"retention_days,omitempty"`
+}
+
+// Client - a document in the clients collection. API keys are held as SHA-256 hashes, see HashAPIKey.
+type Client struct {
+	ClientID           string   `json:"clientid" firestore:"clientid"`
+	Name               string   `json:"name" firestore:"name"`
+	IssuerIDs          []string `json:"issuer_ids" firestore:"issuer_ids"`
+	SoftwareProviderID int      `json:"software_id" firestore:"software_id"`
+	APIKeyHashes       []string `json:"-" firestore:"api_key_hashes"`
+	Settings           Settings `json:"settings" firestore:"settings"`
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash of an API key as stored in Client.APIKeyHashes
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// FromClientID loads the client with the given client id
+func FromClientID(ctx context.Context, clientID string, fs *firestore.Client) (Client, error) {
+	c, err := first(ctx, fs.Collection(CLIENTS_COLLECTION).Where("clientid", "==", clientID).Limit(1))
+	if err != nil {
+		if !errors.Is(err, ErrClientNotFound) {
+			log.Errorf("FromClientID:[%s]:%v", clientID, err)
+		}
+		return c, fmt.Errorf("%w with client id [%s]", err, clientID)
+	}
+	return c, nil
+}
+
+// FromAPIKey loads the client owning the given API key
+func FromAPIKey(ctx context.Context, apiKey string, fs *firestore.Client) (Client, error) {
+
+	if len(apiKey) == 0 {
+		return Client{}, fmt.Errorf("%w: missing API Key", ErrClientNotFound)
+	}
+
+	c, err := first(ctx, fs.Collection(CLIENTS_COLLECTION).Where("api_key_hashes", "array-contains", HashAPIKey(apiKey)).Limit(1))
+	if err != nil && !errors.Is(err, ErrClientNotFound) {
+		log.Errorf("FromAPIKey:%v", err)
+	}
+	return c, err
+}
+
+// OwnsIssuer returns true if the client may act for the issuer
+func (c Client) OwnsIssuer(issuerID string) bool {
+	for _, id := range c.IssuerIDs {
+		if id == issuerID {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientInfo returns the client details recorded on a search made for the issuer, the issuer must belong to the client
+func (c Client) ClientInfo(issuerID string) (search.ClientInfo, error) {
+
+	if !c.OwnsIssuer(issuerID) {
+		return search.ClientInfo{}, fmt.Errorf("%w [%s] [%s]", ErrIssuerNotForClient, issuerID, c.ClientID)
+	}
+
+	return search.ClientInfo{
+		ClientID:           c.ClientID,
+		IssuerID:           issuerID,
+		SoftwareProviderID: c.SoftwareProviderID,
+	}, nil
+}
+
+// ApplySearchDefaults fills in any search options the request leaves empty with the client's defaults
+func (c Client) ApplySearchDefaults(r *search.Request) {
+	if len(r.VRMCountry) == 0 {
+		r.VRMCountry = c.Settings.DefaultVRMCountry
+	}
+}
+
+// RetentionPeriod returns how long the client's search records are kept
+func (c Client) RetentionPeriod() time.Duration {
+	days := c.Settings.RetentionDays
+	if days <= 0 {
+		days = DefaultRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// RetainUntil returns when a search made at searchDate may be deleted
+func (c Client) RetainUntil(searchDate time.Time) time.Time {
+	return searchDate.Add(c.RetentionPeriod())
+}
+
+func first(ctx context.Context, q firestore.Query) (Client, error) {
+
+	c := Client{}
+
+	itr := q.Documents(ctx)
+	defer itr.Stop()
+
+	doc, err := itr.Next()
+	if err != nil {
+		if errors.Is(err, iterator.Done) {
+			return c, ErrClientNotFound
+		}
+		return c, err
+	}
+
+	if err = doc.DataTo(&c); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,150 @@
+package client_information
+
+import (
+	"context"
+	"errors"
+	"github.com/transfer360/go-transfer360/search"
+	"testing"
+	"time"
+)
+
+func testClient() Client {
+	return Client{
+		ClientID:           "C1",
+		Name:               "Client One",
+		IssuerIDs:          []string{"T1", "T2"},
+		SoftwareProviderID: 7,
+	}
+}
+
+func TestOwnsIssuer(t *testing.T) {
+
+	tests := []struct {
+		issuerID string
+		want     bool
+	}{
+		{"T1", true},
+		{"T2", true},
+		{"T3", false},
+		{"t1", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.issuerID, func(t *testing.T) {
+			if got := testClient().OwnsIssuer(tt.issuerID); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if (Client{}).OwnsIssuer("T1") {
+		t.Error("a client without issuers owns nothing")
+	}
+}
+
+func TestClientInfo(t *testing.T) {
+
+	got, err := testClient().ClientInfo("T2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (search.ClientInfo{ClientID: "C1", IssuerID: "T2", SoftwareProviderID: 7}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	got, err = testClient().ClientInfo("T3")
+	if !errors.Is(err, ErrIssuerNotForClient) {
+		t.Errorf("got %v, want %v", err, ErrIssuerNotForClient)
+	}
+	if got != (search.ClientInfo{}) {
+		t.Errorf("expected no client info, got %+v", got)
+	}
+}
+
+func TestApplySearchDefaults(t *testing.T) {
+
+	c := testClient()
+	c.Settings.DefaultVRMCountry = "IE"
+
+	tests := []struct {
+		name    string
+		client  Client
+		country string
+		want    string
+	}{
+		{"empty country uses the default", c, "", "IE"},
+		{"given country is kept", c, "FR", "FR"},
+		{"no default", testClient(), "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := search.Request{VRMCountry: tt.country}
+			tt.client.ApplySearchDefaults(&r)
+			if r.VRMCountry != tt.want {
+				t.Errorf("got %q, want %q", r.VRMCountry, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetentionPeriod(t *testing.T) {
+
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name string
+		days int
+		want time.Duration
+	}{
+		{"default", 0, DefaultRetentionDays * day},
+		{"negative uses the default", -5, DefaultRetentionDays * day},
+		{"set", 30, 30 * day},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Client{Settings: Settings{RetentionDays: tt.days}}
+			if got := c.RetentionPeriod(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	searched := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	if got, want := (Client{Settings: Settings{RetentionDays: 30}}).RetainUntil(searched), time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("retain until %v, want %v", got, want)
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := HashAPIKey(tt.key); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if HashAPIKey("key-1") == HashAPIKey("key-2") {
+		t.Error("different keys should hash differently")
+	}
+}
+
+func TestFromAPIKeyMissing(t *testing.T) {
+
+	// a missing key is refused before Firestore is queried
+	if _, err := FromAPIKey(context.Background(), "", nil); !errors.Is(err, ErrClientNotFound) {
+		t.Errorf("got %v, want %v", err, ErrClientNotFound)
+	}
+}
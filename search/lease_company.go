@@ -1,5 +1,10 @@
 package search
 
+import (
+	"github.com/transfer360/go-transfer360/address"
+	"github.com/transfer360/go-transfer360/postcode"
+)
+
 type LeaseCompanyAddress struct {
 	Companyname  string `json:"companyname,omitempty"`
 	AddressLine1 string `json:"address_line1,omitempty"`
@@ -8,3 +13,29 @@ type LeaseCompanyAddress struct {
 	AddressLine4 string `json:"address_line4,omitempty"`
 	Postcode     string `json:"postcode,omitempty"`
 }
+
+// ValidatePostcode checks the postcode against the UK postcode grammar
+func (a LeaseCompanyAddress) ValidatePostcode() error {
+	_, err := postcode.Normalise(a.Postcode)
+	return err
+}
+
+// NormalisePostcode rewrites the postcode in upper case with standard spacing, the postcode is left unchanged if it
+// is not valid
+func (a *LeaseCompanyAddress) NormalisePostcode() error {
+	pc, err := postcode.Normalise(a.Postcode)
+	if err != nil {
+		return err
+	}
+	a.Postcode = pc
+	return nil
+}
+
+// PostalAddress returns the lease company address ready for formatting with address.Format
+func (a LeaseCompanyAddress) PostalAddress() address.Address {
+	return address.Address{
+		Organisation: a.Companyname,
+		Lines:        []string{a.AddressLine1, a.AddressLine2, a.AddressLine3, a.AddressLine4},
+		Postcode:     a.Postcode,
+	}
+}
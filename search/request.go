@@ -1,9 +1,12 @@
 package search
 
 import (
+	"errors"
 	"fmt"
 	"github.com/asaskevich/govalidator"
 	"github.com/go-playground/validator/v10"
+	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/vrm"
 	"time"
 )
 
@@ -15,12 +18,33 @@ type Request struct {
 	// a reference number which goes with the vehicle registration - required field
 	Reference  string `json:"your_reference" validate:"required"`
 	InitalSref string `json:"inital_sref,omitempty"` // if a search reference is generated and passed through
+	// ISO 3166-1 alpha-2 country of the vehicle registration - optional, defaults to GB
+	VRMCountry string `json:"vrm_country,omitempty"`
 }
 
+// Validate checks the request before it is sent. A registration given as GB, or with no country, which only matches
+// a foreign format is refused with vrm.ErrForeignRegistration so it is not searched as a UK plate. A registration
+// which does not match a known format for another country is logged as a warning rather than refused. The request
+// is not changed, use Normalised for the cleaned copy which is sent.
 func (sr *Request) Validate() error {
 
 	dateNow := time.Now()
 
+	country, err := vrm.NormaliseCountryCode(sr.VRMCountry)
+	if err != nil {
+		return err
+	}
+
+	if len(sr.VRM) > 0 {
+		_, err = vrm.Normalise(sr.VRM, country)
+		switch {
+		case errors.Is(err, vrm.ErrForeignRegistration):
+			return err
+		case err != nil:
+			log.Warnf("%s | %v", sr.Reference, err)
+		}
+	}
+
 	if !govalidator.IsRFC3339(sr.DateTime) {
 		return fmt.Errorf("invalid datetime format, should be RFC3339 - please see documentation")
 	}
@@ -35,3 +59,29 @@ func (sr *Request) Validate() error {
 	return validate.Struct(sr)
 
 }
+
+// Normalised returns a copy of the request with the registration cleaned and the country given as its ISO 3166-1
+// code. An error is returned for an unknown country or a registration given as GB which is not a UK format.
+func (sr Request) Normalised() (Request, error) {
+
+	country, err := vrm.NormaliseCountryCode(sr.VRMCountry)
+	if err != nil {
+		return sr, err
+	}
+
+	reg, err := vrm.Normalise(sr.VRM, country)
+	switch {
+	case errors.Is(err, vrm.ErrForeignRegistration):
+		return sr, err
+	case err != nil:
+		// kept as given, Validate warns about registrations which match no format for their country
+		reg = vrm.Clean(sr.VRM)
+	}
+
+	sr.VRM = reg
+	if len(sr.VRMCountry) > 0 {
+		sr.VRMCountry = country
+	}
+
+	return sr, nil
+}
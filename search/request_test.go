@@ -0,0 +1,58 @@
+package search
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/transfer360/go-transfer360/vrm"
+)
+
+func TestRequestValidate(t *testing.T) {
+
+	contravention := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name    string
+		req     Request
+		wantVRM string
+		wantCC  string
+		err     error
+		wantErr bool
+	}{
+		{"uk", Request{VRM: "ab12 cde", DateTime: contravention, Reference: "R1"}, "AB12CDE", "", nil, false},
+		{"uk with country", Request{VRM: "AB12CDE", VRMCountry: "gb", DateTime: contravention, Reference: "R1"}, "AB12CDE", "GB", nil, false},
+		{"foreign plate with no country", Request{VRM: "AB-123-CD", DateTime: contravention, Reference: "R1"}, "", "", vrm.ErrForeignRegistration, true},
+		{"foreign plate as gb", Request{VRM: "AB-123-CD", VRMCountry: "GB", DateTime: contravention, Reference: "R1"}, "", "", vrm.ErrForeignRegistration, true},
+		{"french", Request{VRM: "AB-123-CD", VRMCountry: "France", DateTime: contravention, Reference: "R1"}, "AB123CD", "FR", nil, false},
+		{"unrecognised format for country is kept", Request{VRM: "CD 1234", VRMCountry: "FR", DateTime: contravention, Reference: "R1"}, "CD1234", "FR", nil, false},
+		{"invalid country", Request{VRM: "AB12CDE", VRMCountry: "Atlantis", DateTime: contravention, Reference: "R1"}, "", "", vrm.ErrInvalidCountryCode, true},
+		{"future", Request{VRM: "AB12CDE", DateTime: time.Now().Add(time.Hour).Format(time.RFC3339), Reference: "R1"}, "", "", nil, true},
+		{"missing reference", Request{VRM: "AB12CDE", DateTime: contravention}, "", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			given := tt.req
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr || (tt.err != nil && !errors.Is(err, tt.err)) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+			if tt.req != given {
+				t.Errorf("Validate changed the request to %+v", tt.req)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			n, err := tt.req.Normalised()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n.VRM != tt.wantVRM || n.VRMCountry != tt.wantCC {
+				t.Errorf("got %q %q, want %q %q", n.VRM, n.VRMCountry, tt.wantVRM, tt.wantCC)
+			}
+		})
+	}
+}
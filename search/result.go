@@ -12,6 +12,7 @@ type Result struct {
 	Sref              string              `json:"sref"`
 	IsHirerVehicle    bool                `json:"is_hirer_vehicle"`
 	VRM               string              `json:"vrm"`
+	VRMCountry        string              `json:"vrm_country,omitempty"`
 	ContraventionDate string              `json:"contravention_date"`
 	Reference         string              `json:"your_reference"`
 	LeaseCompany      LeaseCompanyAddress `json:"lease_company,omitempty"`
@@ -6,14 +6,17 @@ import (
 	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/software_provider"
 	"google.golang.org/api/iterator"
 	"time"
 )
 
 type ClientInfo struct {
-	ClientID           string `firestore:"clientid"`
-	IssuerID           string `json:"issuer_id" firestore:"issuer_id,omitempty"`
-	SoftwareProviderID int    `firestore:"software_id,omitempty"`
+	ClientID string `firestore:"clientid"`
+	IssuerID string `json:"issuer_id" firestore:"issuer_id,omitempty"`
+	// SoftwareProviderID - set through CreateSearchRecord.SetSoftwareProvider, which checks the provider is enabled
+	// for searches. Setting it directly skips that check.
+	SoftwareProviderID int `firestore:"software_id,omitempty"`
 }
 
 type SearchResult struct {
@@ -28,7 +31,7 @@ type SearchResult struct {
 }
 
 type NoticeInformation struct {
-	NoticeType          int    `json:"-" firestore:"notice_type,omitempty"`
+	NoticeType          int    `json:"-" firestore:"notice_type,omitempty"` // see notices.Type
 	NoticeNumber        string `json:"notice_number,omitempty" firestore:"notice_number,omitempty"`
 	VehicleRegistration string `json:"vehicle_registration,omitempty" firestore:"vehicle_registration,omitempty"`
 	NoticeReceived      string `json:"-" firestore:"notice_received,omitempty"`
@@ -47,6 +50,26 @@ type CreateSearchRecord struct {
 
 const SEARCHES_COLLECTION = "searches"
 
+// SetSoftwareProvider looks up the software provider and records it on the search, the provider must be enabled for
+// searches
+func (s *CreateSearchRecord) SetSoftwareProvider(ctx context.Context, id int, store software_provider.Store) error {
+
+	p, err := software_provider.GetSoftwareProvider(ctx, id, store)
+	if err != nil {
+		return err
+	}
+
+	if err = p.CheckEndpoint(software_provider.EndpointSearch); err != nil {
+		return err
+	}
+
+	s.Client.SoftwareProviderID = p.ID
+	return nil
+}
+
+// Save writes the search record as it is. The software provider is not looked up again here, Save has no provider
+// store and searches saved before the registry existed carry provider ids it doesn't hold. Callers recording a
+// provider use SetSoftwareProvider first.
 func (s CreateSearchRecord) Save(ctx context.Context, client *firestore.Client) (docref string, err error) {
 
 	collection := SEARCHES_COLLECTION
@@ -0,0 +1,47 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"github.com/transfer360/go-transfer360/software_provider"
+	"testing"
+)
+
+func TestSetSoftwareProvider(t *testing.T) {
+
+	store := software_provider.NewMemoryStore(
+		software_provider.SoftwareProvider{ID: 1, Name: "Searcher", Endpoints: []software_provider.Endpoint{software_provider.EndpointSearch}},
+		software_provider.SoftwareProvider{ID: 2, Name: "Notices only", Endpoints: []software_provider.Endpoint{software_provider.EndpointParkingCharge}},
+		software_provider.SoftwareProvider{ID: 3, Name: "Disabled", Disabled: true, Endpoints: []software_provider.Endpoint{software_provider.EndpointSearch}},
+	)
+
+	tests := []struct {
+		name string
+		id   int
+		want error
+	}{
+		{"enabled for searches", 1, nil},
+		{"not enabled for searches", 2, software_provider.ErrEndpointNotEnabled},
+		{"disabled", 3, software_provider.ErrSoftwareProviderDisabled},
+		{"unknown", 4, software_provider.ErrSoftwareProviderNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			s := CreateSearchRecord{Sref: "S1"}
+			err := s.SetSoftwareProvider(context.Background(), tt.id, store)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+
+			want := 0
+			if tt.want == nil {
+				want = tt.id
+			}
+			if s.Client.SoftwareProviderID != want {
+				t.Errorf("software provider %d, want %d", s.Client.SoftwareProviderID, want)
+			}
+		})
+	}
+}
@@ -15,8 +15,21 @@ import (
 
 	joonix "github.com/joonix/log"
 	log "github.com/sirupsen/logrus"
+	"github.com/transfer360/go-transfer360/apikey"
 )
 
+// SendEnquiryForIssuer sends the search using the API key resolved for the issuer, for software providers with a
+// different key per client
+func SendEnquiryForIssuer(ctx context.Context, n Request, issuerID string, keys apikey.APIKeyResolver) (Result, error) {
+
+	apiKey, err := keys.APIKey(ctx, issuerID)
+	if err != nil {
+		return Result{}, fmt.Errorf("resolving API key: %w", err)
+	}
+
+	return SendEnquiry(ctx, n, apiKey)
+}
+
 func SendEnquiry(ctx context.Context, n Request, apiKey string) (scanReturn Result, err error) {
 
 	if len(os.Getenv("DEVELOPMENT")) == 0 {
@@ -36,6 +49,9 @@ func SendEnquiry(ctx context.Context, n Request, apiKey string) (scanReturn Resu
 		return scanReturn, err
 	}
 
+	// send the cleaned registration, Validate has already refused anything Normalised would
+	n, _ = n.Normalised()
+
 	url := "https://api.transfer360.io/search"
 
 	jsonStr, err := json.Marshal(n)
@@ -85,6 +101,7 @@ func SendEnquiry(ctx context.Context, n Request, apiKey string) (scanReturn Resu
 		}
 
 		scanReturn.VRM = n.VRM
+		scanReturn.VRMCountry = n.VRMCountry
 		scanReturn.Reference = n.Reference
 		scanReturn.ContraventionDate = n.DateTime
 		scanReturn.Sref = sr.Sref
@@ -0,0 +1,81 @@
+package software_provider
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
+	"strconv"
+)
+
+// FirestoreStore - keeps software providers in the software_providers collection, one document per provider with the
+// provider id as the document id
+type FirestoreStore struct {
+	fs *firestore.Client
+}
+
+func NewFirestoreStore(fs *firestore.Client) *FirestoreStore {
+	return &FirestoreStore{fs: fs}
+}
+
+func (s *FirestoreStore) Get(ctx context.Context, id int) (SoftwareProvider, error) {
+
+	p := SoftwareProvider{}
+
+	doc, err := s.fs.Collection(SOFTWARE_PROVIDERS_COLLECTION).Doc(strconv.Itoa(id)).Get(ctx)
+	if err != nil {
+		if doc != nil && !doc.Exists() {
+			return p, fmt.Errorf("%w [%d]", ErrSoftwareProviderNotFound, id)
+		}
+		log.Errorf("FirestoreStore.Get:[%d]:%v", id, err)
+		return p, err
+	}
+
+	if err = doc.DataTo(&p); err != nil {
+		log.Errorf("FirestoreStore.Get:[%d]:%v", id, err)
+		return p, err
+	}
+
+	return p, nil
+}
+
+func (s *FirestoreStore) Save(ctx context.Context, p SoftwareProvider) error {
+
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.fs.Collection(SOFTWARE_PROVIDERS_COLLECTION).Doc(strconv.Itoa(p.ID)).Set(ctx, p)
+	if err != nil {
+		log.Errorf("FirestoreStore.Save:[%d]:%v", p.ID, err)
+	}
+	return err
+}
+
+func (s *FirestoreStore) List(ctx context.Context) ([]SoftwareProvider, error) {
+
+	var providers []SoftwareProvider
+
+	itr := s.fs.Collection(SOFTWARE_PROVIDERS_COLLECTION).OrderBy("id", firestore.Asc).Documents(ctx)
+	for {
+		doc, err := itr.Next()
+		if err != nil {
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			log.Errorf("FirestoreStore.List:%v", err)
+			return nil, err
+		}
+
+		p := SoftwareProvider{}
+		if err = doc.DataTo(&p); err != nil {
+			log.Errorf("FirestoreStore.List:[%s]:%v", doc.Ref.ID, err)
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+
+	return providers, nil
+}
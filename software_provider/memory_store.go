@@ -0,0 +1,61 @@
+package software_provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemoryStore - keeps software providers in memory, for tests and services which load their configuration at start up
+type MemoryStore struct {
+	mu        sync.RWMutex
+	providers map[int]SoftwareProvider
+}
+
+// NewMemoryStore creates a MemoryStore holding the given providers, they are not validated
+func NewMemoryStore(providers ...SoftwareProvider) *MemoryStore {
+	s := &MemoryStore{providers: map[int]SoftwareProvider{}}
+	for _, p := range providers {
+		s.providers[p.ID] = p
+	}
+	return s
+}
+
+func (s *MemoryStore) Get(_ context.Context, id int) (SoftwareProvider, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.providers[id]
+	if !ok {
+		return p, fmt.Errorf("%w [%d]", ErrSoftwareProviderNotFound, id)
+	}
+	return p, nil
+}
+
+func (s *MemoryStore) Save(_ context.Context, p SoftwareProvider) error {
+
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.providers[p.ID] = p
+	return nil
+}
+
+func (s *MemoryStore) List(_ context.Context) ([]SoftwareProvider, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	providers := make([]SoftwareProvider, 0, len(s.providers))
+	for _, p := range s.providers {
+		providers = append(providers, p)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].ID < providers[j].ID
+	})
+	return providers, nil
+}
@@ -0,0 +1,110 @@
+// Package software_provider holds the registry of software providers integrating with Transfer360 and their
+// per-provider configuration
+package software_provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"strings"
+)
+
+const SOFTWARE_PROVIDERS_COLLECTION = "software_providers"
+
+var ErrSoftwareProviderNotFound = errors.New("software provider not found")
+var ErrSoftwareProviderDisabled = errors.New("software provider is disabled")
+var ErrEndpointNotEnabled = errors.New("endpoint is not enabled for software provider")
+
+// Endpoint - a Transfer360 API endpoint a software provider can be enabled for
+type Endpoint string
+
+const (
+	EndpointSearch        Endpoint = "search"
+	EndpointParkingCharge Endpoint = "notice/parking_charge"
+)
+
+type Contact struct {
+	Name  string `json:"name,omitempty" firestore:"name,omitempty"`
+	Email string `json:"email,omitempty" firestore:"email,omitempty" validate:"omitempty,email"`
+	Phone string `json:"phone,omitempty" firestore:"phone,omitempty"`
+}
+
+// NoticeOptions - defaults applied to notices sent on behalf of the provider's issuers
+type NoticeOptions struct {
+	// VRMCountry - vehicle country used when a notice does not give one
+	VRMCountry string `json:"vrm_country,omitempty" firestore:"vrm_country,omitempty"`
+}
+
+// SoftwareProvider - a document in the software_providers collection, keyed by ID
+type SoftwareProvider struct {
+	ID      int     `json:"id" firestore:"id" validate:"gt=0"`
+	Name    string  `json:"name" firestore:"name" validate:"required"`
+	Contact Contact `json:"contact" firestore:"contact"`
+	// Disabled providers are refused by GetSoftwareProvider
+	Disabled  bool       `json:"disabled,omitempty" firestore:"disabled"`
+	Endpoints []Endpoint `json:"endpoints" firestore:"endpoints"`
+	// APIKeyRefs - where the provider's API keys are held, e.g. a Secret Manager resource name or environment variable
+	// name, never the key itself
+	APIKeyRefs    []string      `json:"api_key_refs,omitempty" firestore:"api_key_refs,omitempty"`
+	NoticeOptions NoticeOptions `json:"notice_options" firestore:"notice_options"`
+}
+
+// Store - somewhere software providers are kept
+type Store interface {
+	Get(ctx context.Context, id int) (SoftwareProvider, error)
+	Save(ctx context.Context, p SoftwareProvider) error
+	List(ctx context.Context) ([]SoftwareProvider, error)
+}
+
+// Validate checks the required fields are present
+func (p *SoftwareProvider) Validate() error {
+
+	p.Name = strings.TrimSpace(p.Name)
+
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// Enabled returns true if the provider may use the endpoint
+func (p SoftwareProvider) Enabled(e Endpoint) bool {
+	if p.Disabled {
+		return false
+	}
+	for _, pe := range p.Endpoints {
+		if pe == e {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEndpoint returns an error if the provider is disabled or not enabled for the endpoint
+func (p SoftwareProvider) CheckEndpoint(e Endpoint) error {
+	if p.Disabled {
+		return fmt.Errorf("%w [%d]", ErrSoftwareProviderDisabled, p.ID)
+	}
+	if !p.Enabled(e) {
+		return fmt.Errorf("%w [%d] %s", ErrEndpointNotEnabled, p.ID, e)
+	}
+	return nil
+}
+
+// GetSoftwareProvider returns the software provider with the given id, refusing providers which are disabled
+func GetSoftwareProvider(ctx context.Context, id int, store Store) (SoftwareProvider, error) {
+
+	if id <= 0 {
+		return SoftwareProvider{}, fmt.Errorf("%w [%d]", ErrSoftwareProviderNotFound, id)
+	}
+
+	p, err := store.Get(ctx, id)
+	if err != nil {
+		return p, err
+	}
+
+	if p.Disabled {
+		return p, fmt.Errorf("%w [%d]", ErrSoftwareProviderDisabled, id)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,115 @@
+package software_provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+
+	ctx := context.Background()
+	s := NewMemoryStore(
+		SoftwareProvider{ID: 3, Name: "Gamma"},
+		SoftwareProvider{ID: 1, Name: "Alpha"},
+	)
+
+	p, err := s.Get(ctx, 1)
+	if err != nil || p.Name != "Alpha" {
+		t.Errorf("got %+v %v, want Alpha", p, err)
+	}
+
+	if _, err = s.Get(ctx, 2); !errors.Is(err, ErrSoftwareProviderNotFound) {
+		t.Errorf("got %v, want %v", err, ErrSoftwareProviderNotFound)
+	}
+
+	if err = s.Save(ctx, SoftwareProvider{ID: 2, Name: "  Beta  "}); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ = s.Get(ctx, 2); p.Name != "Beta" {
+		t.Errorf("expected the saved name to be trimmed, got %q", p.Name)
+	}
+
+	// replaces the provider with the same id
+	if err = s.Save(ctx, SoftwareProvider{ID: 1, Name: "Alpha Two"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, invalid := range []SoftwareProvider{{ID: 0, Name: "Zero"}, {ID: 4, Name: " "}, {ID: 5, Name: "Bad", Contact: Contact{Email: "not an email"}}} {
+		if err = s.Save(ctx, invalid); err == nil {
+			t.Errorf("expected %+v to be refused", invalid)
+		}
+	}
+
+	list, err := s.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range list {
+		names = append(names, p.Name)
+	}
+	if want := []string{"Alpha Two", "Beta", "Gamma"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+func TestCheckEndpoint(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		provider SoftwareProvider
+		endpoint Endpoint
+		want     error
+	}{
+		{"enabled", SoftwareProvider{ID: 1, Endpoints: []Endpoint{EndpointSearch}}, EndpointSearch, nil},
+		{"other endpoint only", SoftwareProvider{ID: 1, Endpoints: []Endpoint{EndpointParkingCharge}}, EndpointSearch, ErrEndpointNotEnabled},
+		{"no endpoints", SoftwareProvider{ID: 1}, EndpointParkingCharge, ErrEndpointNotEnabled},
+		{"disabled", SoftwareProvider{ID: 1, Disabled: true, Endpoints: []Endpoint{EndpointSearch}}, EndpointSearch, ErrSoftwareProviderDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.provider.CheckEndpoint(tt.endpoint)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+			if enabled := tt.provider.Enabled(tt.endpoint); enabled != (tt.want == nil) {
+				t.Errorf("Enabled returned %v", enabled)
+			}
+		})
+	}
+}
+
+func TestGetSoftwareProvider(t *testing.T) {
+
+	s := NewMemoryStore(
+		SoftwareProvider{ID: 1, Name: "Alpha"},
+		SoftwareProvider{ID: 2, Name: "Beta", Disabled: true},
+	)
+
+	tests := []struct {
+		name string
+		id   int
+		want error
+	}{
+		{"enabled", 1, nil},
+		{"disabled", 2, ErrSoftwareProviderDisabled},
+		{"unknown", 3, ErrSoftwareProviderNotFound},
+		{"zero id", 0, ErrSoftwareProviderNotFound},
+		{"negative id", -1, ErrSoftwareProviderNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetSoftwareProvider(context.Background(), tt.id, s)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+			if err == nil && p.ID != tt.id {
+				t.Errorf("got provider %d, want %d", p.ID, tt.id)
+			}
+		})
+	}
+}
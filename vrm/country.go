@@ -0,0 +1,53 @@
+package vrm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/transfer360/go-transfer360/country"
+)
+
+// ErrInvalidCountryCode - error raised when a vehicle country is not a two letter ISO 3166-1 code
+var ErrInvalidCountryCode = errors.New("invalid vehicle country code")
+
+// DefaultCountry - country assumed when a registration is given without one
+const DefaultCountry = "GB"
+
+// NormaliseCountryCode returns the upper case ISO 3166-1 alpha-2 code for a vehicle country, an empty code is
+// treated as DefaultCountry and names or aliases known to the country package are mapped to their code
+func NormaliseCountryCode(code string) (string, error) {
+
+	if len(strings.TrimSpace(code)) == 0 {
+		return DefaultCountry, nil
+	}
+
+	if c, err := country.Normalise(code); err == nil {
+		return c, nil
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 || !isLetters(code) {
+		return "", fmt.Errorf("%w [%s]", ErrInvalidCountryCode, code)
+	}
+
+	return code, nil
+}
+
+// IsUK returns true if the country code refers to a UK registered vehicle
+func IsUK(code string) bool {
+	c, err := NormaliseCountryCode(code)
+	if err != nil {
+		return false
+	}
+	return c == "GB"
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
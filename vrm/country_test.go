@@ -0,0 +1,44 @@
+package vrm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormaliseCountryCode(t *testing.T) {
+
+	tests := []struct {
+		code string
+		want string
+		err  error
+	}{
+		{"", DefaultCountry, nil},
+		{" gb ", "GB", nil},
+		{"ie", "IE", nil},
+		{"United Kingdom", "GB", nil},
+		{"G1", "", ErrInvalidCountryCode},
+		{"GBR", "GB", nil},
+		{"Atlantis", "", ErrInvalidCountryCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := NormaliseCountryCode(tt.code)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUK(t *testing.T) {
+
+	for code, want := range map[string]bool{"": true, "gb": true, "IE": false, "???": false} {
+		if got := IsUK(code); got != want {
+			t.Errorf("IsUK(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
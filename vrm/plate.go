@@ -0,0 +1,122 @@
+// Package vrm normalises and validates vehicle registration marks for UK and common European countries
+package vrm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidRegistration - error raised when a registration does not match any format for its country
+var ErrInvalidRegistration = errors.New("invalid vehicle registration")
+
+// ErrForeignRegistration - error raised when a registration given as GB only matches a foreign format
+var ErrForeignRegistration = errors.New("vehicle registration is not a UK format, please supply the vehicle country")
+
+var ukFormats = []*regexp.Regexp{
+	regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z]{3}$`), // current AB12CDE
+	regexp.MustCompile(`^[A-Z][0-9]{1,3}[A-Z]{3}$`),  // prefix A123BCD
+	regexp.MustCompile(`^[A-Z]{3}[0-9]{1,3}[A-Z]$`),  // suffix ABC123D
+	regexp.MustCompile(`^[A-Z]{1,3}[0-9]{1,4}$`),     // dateless and Northern Ireland ABZ1234
+	regexp.MustCompile(`^[0-9]{1,4}[A-Z]{1,3}$`),     // dateless reversed 1234AB
+}
+
+// countryFormats - registration formats for common EU countries once separators have been removed
+var countryFormats = map[string][]*regexp.Regexp{
+	"IE": {
+		regexp.MustCompile(`^[0-9]{2,3}[A-Z]{1,2}[0-9]{1,6}$`), // 191D12345
+	},
+	"FR": {
+		regexp.MustCompile(`^[A-Z]{2}[0-9]{3}[A-Z]{2}$`),     // AB123CD
+		regexp.MustCompile(`^[0-9]{1,4}[A-Z]{2,3}[0-9]{2}$`), // pre 2009 FNI 1234AB75
+	},
+	"DE": {
+		regexp.MustCompile(`^[A-ZÄÖÜ]{1,3}[A-Z]{1,2}[0-9]{1,4}[EH]?$`), // BAB1234
+	},
+	"NL": {
+		regexp.MustCompile(`^[A-Z0-9]{6}$`), // sidecodes, checked for mixed letters and digits below
+	},
+	"BE": {
+		regexp.MustCompile(`^[0-9][A-Z]{3}[0-9]{3}$`), // 1ABC123
+		regexp.MustCompile(`^[A-Z]{3}[0-9]{3}$`),      // ABC123
+	},
+	"ES": {
+		regexp.MustCompile(`^[0-9]{4}[BCDFGHJKLMNPRSTVWXYZ]{3}$`), // 1234BCD
+		regexp.MustCompile(`^[A-Z]{1,2}[0-9]{4}[A-Z]{0,2}$`),      // provincial M1234AB
+	},
+	"IT": {
+		regexp.MustCompile(`^[A-Z]{2}[0-9]{3}[A-Z]{2}$`), // AB123CD
+	},
+	"PT": {
+		regexp.MustCompile(`^([A-Z]{2}[0-9]{2}[A-Z]{2}|[0-9]{2}[A-Z]{2}[0-9]{2}|[A-Z]{2}[0-9]{4}|[0-9]{4}[A-Z]{2})$`),
+	},
+	"PL": {
+		regexp.MustCompile(`^[A-Z]{2,3}[A-Z0-9]{4,5}$`), // WA12345
+	},
+}
+
+var genericFormat = regexp.MustCompile(`^[A-Z0-9]{1,10}$`)
+
+// Clean removes spaces, hyphens and dots from a registration and upper cases it
+func Clean(registration string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(registration)))
+}
+
+// IsUKFormat returns true if the registration matches one of the UK formats
+func IsUKFormat(registration string) bool {
+	return matchesAny(Clean(registration), ukFormats)
+}
+
+// Normalise cleans a registration and validates it against the formats for the given country. An empty country is
+// treated as GB, and a registration given as GB that does not match a UK format is refused rather than being sent
+// as a UK search. Countries without specific rules accept any alphanumeric registration of up to 10 characters.
+func Normalise(registration string, country string) (string, error) {
+
+	country, err := NormaliseCountryCode(country)
+	if err != nil {
+		return "", err
+	}
+
+	reg := Clean(registration)
+	if len(reg) == 0 {
+		return "", fmt.Errorf("%w: registration is empty", ErrInvalidRegistration)
+	}
+
+	if country == "GB" {
+		if !matchesAny(reg, ukFormats) {
+			return "", fmt.Errorf("%w [%s]", ErrForeignRegistration, reg)
+		}
+		return reg, nil
+	}
+
+	formats, ok := countryFormats[country]
+	if !ok {
+		formats = []*regexp.Regexp{genericFormat}
+	}
+
+	if !matchesAny(reg, formats) {
+		return "", fmt.Errorf("%w [%s] for country %s", ErrInvalidRegistration, reg, country)
+	}
+
+	if country == "NL" && (!strings.ContainsAny(reg, "0123456789") || !strings.ContainsAny(reg, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")) {
+		return "", fmt.Errorf("%w [%s] for country %s", ErrInvalidRegistration, reg, country)
+	}
+
+	return reg, nil
+}
+
+func matchesAny(reg string, formats []*regexp.Regexp) bool {
+	for _, f := range formats {
+		if f.MatchString(reg) {
+			return true
+		}
+	}
+	return false
+}
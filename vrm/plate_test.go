@@ -0,0 +1,64 @@
+package vrm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalise(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		registration string
+		country      string
+		want         string
+		err          error
+	}{
+		{"current uk", "ab12 cde", "", "AB12CDE", nil},
+		{"uk with country", "AB12-CDE", "GB", "AB12CDE", nil},
+		{"uk by name", "AB12CDE", "United Kingdom", "AB12CDE", nil},
+		{"prefix", "A123 BCD", "", "A123BCD", nil},
+		{"suffix", "ABC 123D", "", "ABC123D", nil},
+		{"dateless", "ABZ 1234", "", "ABZ1234", nil},
+		{"dateless reversed", "1234 AB", "", "1234AB", nil},
+		{"empty", "  ", "", "", ErrInvalidRegistration},
+		{"french plate as gb", "AB-123-CD", "", "", ErrForeignRegistration},
+		{"french", "AB-123-CD", "fr", "AB123CD", nil},
+		{"irish", "191-D-12345", "IE", "191D12345", nil},
+		{"spanish", "1234 BCD", "ES", "1234BCD", nil},
+		{"spanish vowels", "1234 ABC", "ES", "", ErrInvalidRegistration},
+		{"dutch", "12-ABC-3", "NL", "12ABC3", nil},
+		{"dutch letters only", "ABCDEF", "NL", "", ErrInvalidRegistration},
+		{"unlisted country", "XYZ 987", "CH", "XYZ987", nil},
+		{"unlisted country too long", "ABCDEFGHIJK", "CH", "", ErrInvalidRegistration},
+		{"invalid country", "AB12CDE", "Atlantis", "", ErrInvalidCountryCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalise(tt.registration, tt.country)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+
+	tests := map[string]string{
+		" ab12 cde ": "AB12CDE",
+		"AB-12.CDE":  "AB12CDE",
+		"ab\t12cde":  "AB12CDE",
+		"":           "",
+	}
+
+	for in, want := range tests {
+		if got := Clean(in); got != want {
+			t.Errorf("Clean(%q) = %q, want %q", in, got, want)
+		}
+	}
+}